@@ -0,0 +1,70 @@
+package arch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+	"github.com/lmcanavals/waybar-updates-btw/version"
+)
+
+// Advisory is an Arch Linux vulnerability group affecting installed
+// packages, as reported by arch-audit.
+type Advisory struct {
+	Name     string   `json:"name"`
+	Packages []string `json:"packages"`
+	Status   string   `json:"status"`
+	Severity string   `json:"severity"`
+	Type     string   `json:"type"`
+	Affected string   `json:"affected"`
+	Fixed    string   `json:"fixed"`
+	Issues   []string `json:"issues"`
+}
+
+// HasFix reports whether a fixed version has been released.
+func (a Advisory) HasFix() bool {
+	return a.Fixed != ""
+}
+
+// Audit runs arch-audit and returns the advisories affecting the
+// installed packages, both with and without a fix available.
+func Audit(ctx context.Context, r runner.Runner) ([]Advisory, error) {
+	out, err := runner.Output(ctx, r, "arch-audit", "--json")
+	if err != nil {
+		return nil, fmt.Errorf("running arch-audit: %w", err)
+	}
+	var advs []Advisory
+	if err := json.Unmarshal(out, &advs); err != nil {
+		return nil, fmt.Errorf("decoding arch-audit output: %w", err)
+	}
+	return advs, nil
+}
+
+// Vulnerable indexes advisories by affected package name.
+func Vulnerable(advs []Advisory) map[string][]Advisory {
+	m := make(map[string][]Advisory)
+	for _, a := range advs {
+		for _, p := range a.Packages {
+			m[p] = append(m[p], a)
+		}
+	}
+	return m
+}
+
+// Fixes reports whether u fixes any of the given advisories, bringing its
+// package to at least the fixed version. A fix released only to a testing
+// repository or not yet synced to the mirror doesn't count.
+func Fixes(advs []Advisory, u Update) bool {
+	for _, a := range advs {
+		if !a.HasFix() || version.Compare(u.New, a.Fixed) < 0 {
+			continue
+		}
+		for _, p := range a.Packages {
+			if p == u.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
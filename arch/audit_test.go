@@ -0,0 +1,25 @@
+package arch
+
+import "testing"
+
+func TestFixes(t *testing.T) {
+	advs := []Advisory{
+		{Name: "AVG-2843", Packages: []string{"openssl", "lib32-openssl"}, Fixed: "3.3.1-1"},
+		{Name: "AVG-2900", Packages: []string{"openssl"}},
+	}
+	tests := []struct {
+		u    Update
+		want bool
+	}{
+		{Update{Name: "openssl", Old: "3.3.0-1", New: "3.3.1-1"}, true},
+		{Update{Name: "openssl", Old: "3.3.0-1", New: "3.3.2-1"}, true},
+		{Update{Name: "openssl", Old: "3.3.0-1", New: "3.3.0-2"}, false},
+		{Update{Name: "lib32-openssl", Old: "3.3.0-1", New: "3.3.1-1"}, true},
+		{Update{Name: "curl", Old: "8.7.1-1", New: "8.8.0-1"}, false},
+	}
+	for _, tt := range tests {
+		if got := Fixes(advs, tt.u); got != tt.want {
+			t.Errorf("Fixes(%v) = %v, want %v", tt.u, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package arch
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PackageCache is the default pacman package cache directory.
+const PackageCache = "/var/cache/pacman/pkg"
+
+// CacheInfo summarizes the contents of the package cache.
+type CacheInfo struct {
+	// Size is the total size in bytes, signatures included.
+	Size int64
+	// Versions maps each package name to how many versions are cached.
+	Versions map[string]int
+}
+
+// ReadCache summarizes the package cache at dir.
+func ReadCache(dir string) (CacheInfo, error) {
+	info := CacheInfo{Versions: make(map[string]int)}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return info, fmt.Errorf("reading package cache: %w", err)
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		if fi, err := e.Info(); err == nil {
+			info.Size += fi.Size()
+		}
+		if name, ok := cachedPackage(e.Name()); ok {
+			info.Versions[name]++
+		}
+	}
+	return info, nil
+}
+
+// cachedPackage extracts the package name from a file name like
+// "foo-bar-1.2-3-x86_64.pkg.tar.zst". Signatures and partial downloads are
+// not packages.
+func cachedPackage(file string) (string, bool) {
+	if !strings.Contains(file, ".pkg.tar") || strings.HasSuffix(file, ".sig") || strings.HasSuffix(file, ".part") {
+		return "", false
+	}
+	// name-pkgver-pkgrel-arch: the name is everything before the last three
+	// dash separated fields.
+	fields := strings.Split(file, "-")
+	if len(fields) < 4 {
+		return "", false
+	}
+	return strings.Join(fields[:len(fields)-3], "-"), true
+}
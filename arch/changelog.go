@@ -0,0 +1,21 @@
+package arch
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// Changelog returns up to n lines from the start of the changelog shipped
+// with an installed package, or nothing when it ships none.
+func Changelog(ctx context.Context, r runner.Runner, pkg string, n int) ([]string, error) {
+	lines, err := queryPacman(ctx, r, "-Qc", pkg)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) > 0 && strings.HasPrefix(lines[0], "Changelog for ") {
+		lines = lines[1:]
+	}
+	return lines[:min(max(n, 0), len(lines))], nil
+}
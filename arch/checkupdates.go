@@ -0,0 +1,126 @@
+package arch
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// Update is a pending upgrade of a package.
+type Update struct {
+	Name string
+	Old  string
+	New  string
+}
+
+// ParseUpdate parses a "name old -> new" line as printed by checkupdates.
+// The " [ignored]" pacman -Qu appends for packages in IgnorePkg is dropped.
+func ParseUpdate(line string) (Update, bool) {
+	f := strings.Fields(line)
+	if len(f) == 5 && f[4] == "[ignored]" {
+		f = f[:4]
+	}
+	if len(f) != 4 || f[2] != "->" {
+		return Update{}, false
+	}
+	return Update{Name: f[0], Old: f[1], New: f[3]}, true
+}
+
+// ReadUpdates parses "name old -> new" lines produced by other tools, such
+// as aurutils or custom scripts. Blank lines and lines starting with # are
+// skipped; anything else that doesn't parse is an error.
+func ReadUpdates(r io.Reader) ([]Update, error) {
+	var updates []Update
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		u, ok := ParseUpdate(line)
+		if !ok {
+			return nil, fmt.Errorf("line %d: want \"name old -> new\", got %q", n, line)
+		}
+		updates = append(updates, u)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading updates: %w", err)
+	}
+	return updates, nil
+}
+
+// CheckUpdates runs checkupdates, which syncs a private copy of the
+// databases, and returns the pending official updates. With download set the
+// packages are also fetched into the cache, so the actual upgrade only has
+// to install them.
+func CheckUpdates(ctx context.Context, r runner.Runner, download bool) ([]Update, error) {
+	return checkUpdates(ctx, r, "", download)
+}
+
+// checkUpdates runs checkupdates against the private database in dbDir, or
+// its default location when dbDir is empty.
+func checkUpdates(ctx context.Context, r runner.Runner, dbDir string, download bool) ([]Update, error) {
+	cmd := runner.Command{Name: "checkupdates"}
+	if download {
+		cmd.Args = append(cmd.Args, "--download")
+	}
+	if dbDir != "" {
+		cmd.Env = append(cmd.Env, "CHECKUPDATES_DB="+dbDir)
+	}
+	out, err := r.Output(ctx, cmd)
+	if err != nil {
+		// Exit status 2 means there are no pending updates.
+		if runner.ExitCode(err) == 2 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("running checkupdates: %w", err)
+	}
+	var updates []Update
+	for _, line := range strings.Split(string(out), "\n") {
+		if u, ok := ParseUpdate(line); ok {
+			updates = append(updates, u)
+		}
+	}
+	return updates, nil
+}
+
+// Downloaded reports whether the new version of every update is already in
+// the package cache at dir.
+func Downloaded(dir string, updates []Update) bool {
+	for _, u := range updates {
+		if !cached(dir, u.Name, u.New) {
+			return false
+		}
+	}
+	return true
+}
+
+func cached(dir, name, version string) bool {
+	matches, _ := filepath.Glob(filepath.Join(dir, name+"-"+version+"-*.pkg.tar*"))
+	for _, m := range matches {
+		if !strings.HasSuffix(m, ".sig") && !strings.HasSuffix(m, ".part") {
+			return true
+		}
+	}
+	return false
+}
+
+// KeyringPackage holds the packager keys. With a stale keyring the
+// signatures of newer packages fail to verify, so it should be upgraded
+// before anything else.
+const KeyringPackage = "archlinux-keyring"
+
+// KeyringPending reports whether the keyring is among the updates.
+func KeyringPending(updates []Update) bool {
+	for _, u := range updates {
+		if u.Name == KeyringPackage {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package arch
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+func TestCheckUpdates(t *testing.T) {
+	tests := []struct {
+		name string
+		res  runner.Result
+		want []Update
+	}{
+		{"updates", runner.Result{Stdout: "linux 6.9.1.arch1-1 -> 6.9.2.arch1-1\nvim 9.1.0-1 -> 9.1.1-1\n"}, []Update{
+			{Name: "linux", Old: "6.9.1.arch1-1", New: "6.9.2.arch1-1"},
+			{Name: "vim", Old: "9.1.0-1", New: "9.1.1-1"},
+		}},
+		{"up to date", runner.Result{Code: 2}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &runner.Fake{Results: map[string]runner.Result{"checkupdates": tt.res}}
+			got, err := checkUpdates(context.Background(), r, "/tmp/db", false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			calls := r.Calls()
+			if len(calls) != 1 || !slices.Contains(calls[0].Env, "CHECKUPDATES_DB=/tmp/db") {
+				t.Errorf("calls = %+v, want checkupdates with CHECKUPDATES_DB=/tmp/db", calls)
+			}
+		})
+	}
+}
+
+func TestCheckUpdatesFailure(t *testing.T) {
+	r := &runner.Fake{Results: map[string]runner.Result{"checkupdates": {Code: 1}}}
+	if _, err := CheckUpdates(context.Background(), r, false); err == nil {
+		t.Error("want an error for exit status 1")
+	}
+}
+
+func TestReadUpdates(t *testing.T) {
+	in := `# pacman -Qu
+linux 6.9.1.arch1-1 -> 6.9.2.arch1-1
+
+nvidia 550.78-1 -> 550.90.07-1 [ignored]
+`
+	got, err := ReadUpdates(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Update{
+		{Name: "linux", Old: "6.9.1.arch1-1", New: "6.9.2.arch1-1"},
+		{Name: "nvidia", Old: "550.78-1", New: "550.90.07-1"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, err := ReadUpdates(strings.NewReader("linux 6.9.1 6.9.2\n")); err == nil {
+		t.Error("want an error for a malformed line")
+	}
+}
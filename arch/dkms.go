@@ -0,0 +1,94 @@
+package arch
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// UnbuiltModule is a DKMS module that is not installed for a kernel.
+type UnbuiltModule struct {
+	Module string // name/version
+	Kernel string
+}
+
+// InstalledKernels returns the releases of the kernels installed from
+// packages, the /usr/lib/modules entries that name their pkgbase.
+func InstalledKernels() ([]string, error) {
+	files, err := filepath.Glob("/usr/lib/modules/*/pkgbase")
+	if err != nil {
+		return nil, err
+	}
+	releases := make([]string, len(files))
+	for i, f := range files {
+		releases[i] = filepath.Base(filepath.Dir(f))
+	}
+	return releases, nil
+}
+
+// UnbuiltDKMS returns the DKMS modules missing for any installed kernel,
+// which would leave the system without them at next boot.
+func UnbuiltDKMS(ctx context.Context, r runner.Runner) ([]UnbuiltModule, error) {
+	out, err := runner.Output(ctx, r, "dkms", "status")
+	if err != nil {
+		return nil, fmt.Errorf("running dkms status: %w", err)
+	}
+	kernels, err := InstalledKernels()
+	if err != nil {
+		return nil, err
+	}
+
+	built := make(map[UnbuiltModule]bool)
+	var modules []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		module, kernel, status, ok := parseDKMSStatus(line)
+		if !ok {
+			continue
+		}
+		if !seen[module] {
+			seen[module] = true
+			modules = append(modules, module)
+		}
+		if kernel != "" && status == "installed" {
+			built[UnbuiltModule{module, kernel}] = true
+		}
+	}
+
+	var unbuilt []UnbuiltModule
+	for _, m := range modules {
+		for _, k := range kernels {
+			if u := (UnbuiltModule{m, k}); !built[u] {
+				unbuilt = append(unbuilt, u)
+			}
+		}
+	}
+	return unbuilt, nil
+}
+
+// parseDKMSStatus parses the lines of dkms status, either
+// "nvidia/550.78, 6.9.1-arch1-1, x86_64: installed" or, for modules not
+// built for any kernel, "nvidia/550.78: added". Older dkms releases
+// separate the module version with a comma instead of a slash.
+func parseDKMSStatus(line string) (module, kernel, status string, ok bool) {
+	i := strings.LastIndex(line, ":")
+	if i < 0 {
+		return "", "", "", false
+	}
+	status, _, _ = strings.Cut(strings.TrimSpace(line[i+1:]), " ")
+	f := strings.Split(line[:i], ",")
+	for j := range f {
+		f[j] = strings.TrimSpace(f[j])
+	}
+	if !strings.Contains(f[0], "/") && len(f) >= 2 {
+		f = append([]string{f[0] + "/" + f[1]}, f[2:]...)
+	}
+	module = f[0]
+	if len(f) >= 2 {
+		kernel = f[1]
+	}
+	return module, kernel, status, module != ""
+}
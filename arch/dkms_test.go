@@ -0,0 +1,25 @@
+package arch
+
+import "testing"
+
+func TestParseDKMSStatus(t *testing.T) {
+	tests := []struct {
+		line                   string
+		module, kernel, status string
+		ok                     bool
+	}{
+		{"nvidia/550.78, 6.9.1-arch1-1, x86_64: installed", "nvidia/550.78", "6.9.1-arch1-1", "installed", true},
+		{"nvidia/550.78: added", "nvidia/550.78", "", "added", true},
+		{"vboxhost, 6.1, 5.10.0-1-lts, x86_64: installed (original_module exists)", "vboxhost/6.1", "5.10.0-1-lts", "installed", true},
+		{"vboxhost, 6.1: added", "vboxhost/6.1", "", "added", true},
+		{"no colon here", "", "", "", false},
+		{"", "", "", "", false},
+	}
+	for _, tt := range tests {
+		module, kernel, status, ok := parseDKMSStatus(tt.line)
+		if module != tt.module || kernel != tt.kernel || status != tt.status || ok != tt.ok {
+			t.Errorf("parseDKMSStatus(%q) = %q, %q, %q, %v, want %q, %q, %q, %v",
+				tt.line, module, kernel, status, ok, tt.module, tt.kernel, tt.status, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+// Package arch inspects the state of an Arch Linux system: pending updates
+// from checkupdates, the pacman log and local database, news and security
+// advisories from archlinux.org, orphans and foreign packages, the package
+// cache, pacnew files, mirrors, the keyring, DKMS modules and whether a
+// reboot is required.
+package arch
@@ -0,0 +1,21 @@
+package arch
+
+import (
+	"context"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// Explicit returns the set of packages that were explicitly installed, as
+// opposed to pulled in as dependencies.
+func Explicit(ctx context.Context, r runner.Runner) (map[string]bool, error) {
+	names, err := queryPacman(ctx, r, "-Qqe")
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return set, nil
+}
@@ -0,0 +1,24 @@
+package arch
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// Foreign returns the installed packages not found in any sync database,
+// typically AUR packages, mapped to their installed version.
+func Foreign(ctx context.Context, r runner.Runner) (map[string]string, error) {
+	lines, err := queryPacman(ctx, r, "-Qm")
+	if err != nil {
+		return nil, err
+	}
+	pkgs := make(map[string]string, len(lines))
+	for _, line := range lines {
+		if name, ver, ok := strings.Cut(line, " "); ok {
+			pkgs[name] = ver
+		}
+	}
+	return pkgs, nil
+}
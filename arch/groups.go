@@ -0,0 +1,25 @@
+package arch
+
+import (
+	"context"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// Groups maps each package in the sync databases to the package groups,
+// such as gnome or texlive, that it belongs to.
+func Groups(ctx context.Context, r runner.Runner) (map[string][]string, error) {
+	lines, err := queryPacman(ctx, r, "-Sgg")
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string][]string)
+	for _, line := range lines {
+		// "kde-applications dolphin"
+		if group, pkg, ok := strings.Cut(line, " "); ok {
+			groups[pkg] = append(groups[pkg], group)
+		}
+	}
+	return groups, nil
+}
@@ -0,0 +1,38 @@
+package arch
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+func TestGroups(t *testing.T) {
+	r := &runner.Fake{Results: map[string]runner.Result{"pacman -Sgg": {Stdout: `base-devel autoconf
+base-devel automake
+gnome baobab
+gnome epiphany
+gnome-extra epiphany
+xorg xorg-server
+`}}}
+	got, err := Groups(context.Background(), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{
+		"autoconf":    {"base-devel"},
+		"automake":    {"base-devel"},
+		"baobab":      {"gnome"},
+		"epiphany":    {"gnome", "gnome-extra"},
+		"xorg-server": {"xorg"},
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d packages, want %d", len(got), len(want))
+	}
+	for pkg, groups := range want {
+		if !slices.Equal(got[pkg], groups) {
+			t.Errorf("groups of %s = %q, want %q", pkg, got[pkg], groups)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package arch
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// PacmanGnupg is the pacman keyring directory.
+const PacmanGnupg = "/etc/pacman.d/gnupg"
+
+// Key is a key in the pacman keyring.
+type Key struct {
+	ID      string
+	UID     string
+	Expires time.Time
+}
+
+// ExpiringKeys returns the keys in the pacman keyring that already expired
+// or expire within the given duration of now. Revoked and disabled keys are
+// left out, as that is how retired packager keys are marked.
+func ExpiringKeys(ctx context.Context, r runner.Runner, within time.Duration, now time.Time) ([]Key, error) {
+	out, err := runner.Output(ctx, r, "gpg", "--homedir", PacmanGnupg,
+		"--lock-never", "--no-auto-check-trustdb", "--with-colons", "--list-keys")
+	if err != nil {
+		return nil, fmt.Errorf("listing pacman keys: %w", err)
+	}
+
+	var (
+		keys []Key
+		cur  *Key // the last pub record, until its first uid
+	)
+	for _, line := range strings.Split(string(out), "\n") {
+		f := strings.Split(line, ":")
+		if len(f) < 10 {
+			continue
+		}
+		switch f[0] {
+		case "pub":
+			cur = nil
+			// Field 2 is the validity, r for revoked and d for disabled;
+			// field 12 holds a D for disabled keys too.
+			if f[1] == "r" || f[1] == "d" || len(f) > 11 && strings.Contains(f[11], "D") || f[6] == "" {
+				continue
+			}
+			secs, err := strconv.ParseInt(f[6], 10, 64)
+			if err != nil {
+				continue
+			}
+			if exp := time.Unix(secs, 0); exp.Before(now.Add(within)) {
+				keys = append(keys, Key{ID: f[4], Expires: exp})
+				cur = &keys[len(keys)-1]
+			}
+		case "uid":
+			if cur != nil && cur.UID == "" {
+				cur.UID = f[9]
+			}
+		}
+	}
+	return keys, nil
+}
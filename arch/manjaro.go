@@ -0,0 +1,52 @@
+package arch
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// IsManjaro reports whether the system runs Manjaro, going by the ID in
+// /etc/os-release.
+func IsManjaro() bool {
+	f, err := os.Open("/etc/os-release")
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if id, ok := strings.CutPrefix(sc.Text(), "ID="); ok {
+			return strings.Trim(id, `"`) == "manjaro"
+		}
+	}
+	return false
+}
+
+// ManjaroBranch returns the active Manjaro branch: stable, testing or
+// unstable. It asks pacman-mirrors and falls back to its configuration,
+// where stable is the default.
+func ManjaroBranch(ctx context.Context, r runner.Runner) string {
+	if out, err := runner.Output(ctx, r, "pacman-mirrors", "--get-branch"); err == nil {
+		if b := strings.TrimSpace(string(out)); b != "" {
+			return b
+		}
+	}
+	branch := "stable"
+	f, err := os.Open("/etc/pacman-mirrors.conf")
+	if err != nil {
+		return branch
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		k, v, ok := strings.Cut(sc.Text(), "=")
+		if ok && strings.TrimSpace(k) == "Branch" {
+			branch = strings.TrimSpace(v)
+		}
+	}
+	return branch
+}
@@ -0,0 +1,107 @@
+package arch
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// Mirrorlist is the default pacman mirror list.
+const Mirrorlist = "/etc/pacman.d/mirrorlist"
+
+// UpstreamLastUpdate is the last repository change on the tier 0 mirror,
+// which every other mirror syncs from.
+const UpstreamLastUpdate = "https://rsync.archlinux.org/lastupdate"
+
+// FirstMirror returns the base URL of the first Server in the mirror list at
+// path, the one pacman tries first.
+func FirstMirror(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("reading mirror list: %w", err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		k, v, ok := strings.Cut(sc.Text(), "=")
+		if !ok || strings.TrimSpace(k) != "Server" {
+			continue
+		}
+		// "https://mirror.example/archlinux/$repo/os/$arch"
+		base, _, _ := strings.Cut(strings.TrimSpace(v), "$repo")
+		return strings.TrimSuffix(base, "/"), nil
+	}
+	if err := sc.Err(); err != nil {
+		return "", fmt.Errorf("reading mirror list: %w", err)
+	}
+	return "", errors.New("no Server in mirror list")
+}
+
+// MirrorLag returns how far the mirror at base is behind upstream: the time
+// between the last repository change it has and the last one upstream.
+func MirrorLag(ctx context.Context, client runner.Doer, base string) (time.Duration, error) {
+	upstream, err := fetchTimestamp(ctx, client, UpstreamLastUpdate)
+	if err != nil {
+		return 0, err
+	}
+	mirror, err := fetchTimestamp(ctx, client, base+"/lastupdate")
+	if err != nil {
+		return 0, err
+	}
+	return max(upstream.Sub(mirror), 0), nil
+}
+
+// SyncedAt returns when the sync databases under dbPath, such as
+// /var/lib/pacman, were last refreshed, going by the oldest of them.
+func SyncedAt(dbPath string) (time.Time, error) {
+	files, err := filepath.Glob(filepath.Join(dbPath, "sync", "*.db"))
+	if err != nil {
+		return time.Time{}, err
+	}
+	var oldest time.Time
+	for _, f := range files {
+		fi, err := os.Stat(f)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if oldest.IsZero() || fi.ModTime().Before(oldest) {
+			oldest = fi.ModTime()
+		}
+	}
+	return oldest, nil
+}
+
+// fetchTimestamp reads a file holding a unix timestamp, like lastupdate.
+func fetchTimestamp(ctx context.Context, client runner.Doer, url string) (time.Time, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return time.Time{}, fmt.Errorf("fetching %s: %s", url, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 64))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	secs, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing %s: %w", url, err)
+	}
+	return time.Unix(secs, 0), nil
+}
@@ -0,0 +1,80 @@
+package arch
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// NewsFeed is the Arch Linux news RSS feed.
+const NewsFeed = "https://archlinux.org/feeds/news/"
+
+// NewsItem is a post from the Arch Linux news feed.
+type NewsItem struct {
+	Title     string
+	Link      string
+	Published time.Time
+}
+
+type rss struct {
+	Items []struct {
+		Title   string `xml:"title"`
+		Link    string `xml:"link"`
+		PubDate string `xml:"pubDate"`
+	} `xml:"channel>item"`
+}
+
+// FetchNews downloads the news feed and returns its items, newest first.
+func FetchNews(ctx context.Context, client runner.Doer) ([]NewsItem, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, NewsFeed, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching news: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching news: %s", resp.Status)
+	}
+
+	var feed rss
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("decoding news: %w", err)
+	}
+	items := make([]NewsItem, 0, len(feed.Items))
+	for _, it := range feed.Items {
+		t, err := time.Parse(time.RFC1123Z, it.PubDate)
+		if err != nil {
+			continue
+		}
+		items = append(items, NewsItem{Title: it.Title, Link: it.Link, Published: t})
+	}
+	return items, nil
+}
+
+// UnreadNews returns the news items published after the last full system
+// upgrade, like informant does: anything older was there to be read before
+// the user last upgraded.
+func UnreadNews(ctx context.Context, client runner.Doer, logPath string) ([]NewsItem, error) {
+	since, err := LastUpgrade(logPath)
+	if err != nil {
+		return nil, err
+	}
+	items, err := FetchNews(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	var unread []NewsItem
+	for _, it := range items {
+		if it.Published.After(since) {
+			unread = append(unread, it)
+		}
+	}
+	return unread, nil
+}
@@ -0,0 +1,13 @@
+package arch
+
+import (
+	"context"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// Orphans returns the packages installed as dependencies that nothing
+// requires anymore, as pacman -Qtdq.
+func Orphans(ctx context.Context, r runner.Runner) ([]string, error) {
+	return queryPacman(ctx, r, "-Qtdq")
+}
@@ -0,0 +1,86 @@
+package arch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// queryPacman runs pacman with args and returns its non-empty output
+// lines. pacman exits with status 1 when a query matches nothing, which is
+// reported as no lines rather than as an error; it exits 1 on most failures
+// too, but those print to stderr.
+func queryPacman(ctx context.Context, r runner.Runner, args ...string) ([]string, error) {
+	out, err := runner.Output(ctx, r, "pacman", args...)
+	return pacmanLines(args, out, err)
+}
+
+// pacmanLines interprets the output and error of a pacman run with args
+// like queryPacman.
+func pacmanLines(args []string, out []byte, err error) ([]string, error) {
+	if err != nil {
+		var exitErr *runner.ExitError
+		if errors.As(err, &exitErr) {
+			if exitErr.Code == 1 && len(out) == 0 && len(bytes.TrimSpace(exitErr.Stderr)) == 0 {
+				return nil, nil
+			}
+			if msg := strings.TrimSpace(string(exitErr.Stderr)); msg != "" {
+				return nil, fmt.Errorf("running pacman %s: %w: %s", strings.Join(args, " "), err, msg)
+			}
+		}
+		return nil, fmt.Errorf("running pacman %s: %w", strings.Join(args, " "), err)
+	}
+	var lines []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// PackageRepos maps every package in the sync databases to its repository.
+// dbDir selects the database directory, for instance a SyncDB, and defaults
+// to pacman's own when empty.
+func PackageRepos(ctx context.Context, r runner.Runner, dbDir string) (map[string]string, error) {
+	var args []string
+	if dbDir != "" {
+		args = append(args, "--dbpath", dbDir)
+	}
+	lines, err := queryPacman(ctx, r, append(args, "-Sl")...)
+	if err != nil {
+		return nil, err
+	}
+	repos := make(map[string]string, len(lines))
+	for _, line := range lines {
+		// "core linux 6.9.1.arch1-1 [installed]"
+		if f := strings.Fields(line); len(f) >= 2 {
+			repos[f[1]] = f[0]
+		}
+	}
+	return repos, nil
+}
+
+// parseInfo splits the output of pacman -Si or -Qi into one map of fields
+// per package. Every package starts with its Name field.
+func parseInfo(lines []string) []map[string]string {
+	var pkgs []map[string]string
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if key == "Name" {
+			pkgs = append(pkgs, make(map[string]string))
+		}
+		if len(pkgs) > 0 {
+			pkgs[len(pkgs)-1][key] = value
+		}
+	}
+	return pkgs
+}
@@ -0,0 +1,35 @@
+package arch
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+func TestQueryPacman(t *testing.T) {
+	tests := []struct {
+		name    string
+		res     runner.Result
+		want    []string
+		wantErr bool
+	}{
+		{"lines", runner.Result{Stdout: "glibc\n\n  linux  \n"}, []string{"glibc", "linux"}, false},
+		{"no results", runner.Result{Code: 1}, nil, false},
+		{"failure", runner.Result{Stderr: "error: failed to init transaction (unable to lock database)\n", Code: 1}, nil, true},
+		{"other status", runner.Result{Code: 2}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &runner.Fake{Results: map[string]runner.Result{"pacman -Qdtq": tt.res}}
+			got, err := queryPacman(context.Background(), r, "-Qdtq")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package arch
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// PacmanLog is the default location of the pacman log file.
+const PacmanLog = "/var/log/pacman.log"
+
+const logTimeLayout = "2006-01-02T15:04:05-0700"
+
+// LogEntry is a single line of the pacman log.
+type LogEntry struct {
+	Time    time.Time
+	Source  string // PACMAN, ALPM, ALPM-SCRIPTLET...
+	Message string
+}
+
+// parseLogLine splits a line like
+// "[2024-05-01T10:22:33+0200] [ALPM] upgraded foo (1.0-1 -> 1.1-1)".
+func parseLogLine(line string) (LogEntry, bool) {
+	var e LogEntry
+	if !strings.HasPrefix(line, "[") {
+		return e, false
+	}
+	end := strings.IndexByte(line, ']')
+	if end < 0 {
+		return e, false
+	}
+	t, err := time.Parse(logTimeLayout, line[1:end])
+	if err != nil {
+		return e, false
+	}
+	rest := strings.TrimPrefix(line[end+1:], " ")
+	if !strings.HasPrefix(rest, "[") {
+		return e, false
+	}
+	end = strings.IndexByte(rest, ']')
+	if end < 0 {
+		return e, false
+	}
+	e.Time = t
+	e.Source = rest[1:end]
+	e.Message = strings.TrimPrefix(rest[end+1:], " ")
+	return e, true
+}
+
+// ReadLog parses the pacman log at path, skipping lines it doesn't
+// understand.
+func ReadLog(path string) ([]LogEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening pacman log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []LogEntry
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if e, ok := parseLogLine(sc.Text()); ok {
+			entries = append(entries, e)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading pacman log: %w", err)
+	}
+	return entries, nil
+}
+
+// LastUpgrade returns the time of the last full system upgrade recorded in
+// the pacman log at path, or the zero time when there is none.
+func LastUpgrade(path string) (time.Time, error) {
+	entries, err := ReadLog(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var last time.Time
+	for _, e := range entries {
+		if e.Source == "PACMAN" && e.Message == "starting full system upgrade" {
+			last = e.Time
+		}
+	}
+	return last, nil
+}
+
+// PartialUpgrade looks for a partial upgrade in the pacman log at path: the
+// databases were synced without a full system upgrade following, as with
+// pacman -Sy, and packages were then installed or upgraded against them.
+// It returns when the first such package was installed, or the zero time
+// when the system is fully upgraded against its last sync.
+func PartialUpgrade(path string) (time.Time, error) {
+	entries, err := ReadLog(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var partial time.Time
+	synced := false // synced since the last full upgrade
+	for _, e := range entries {
+		switch {
+		case e.Source == "PACMAN" && e.Message == "synchronizing package lists":
+			synced = true
+		case e.Source == "PACMAN" && e.Message == "starting full system upgrade":
+			synced, partial = false, time.Time{}
+		case synced && partial.IsZero() && e.Source == "ALPM" &&
+			(strings.HasPrefix(e.Message, "installed ") || strings.HasPrefix(e.Message, "upgraded ")):
+			partial = e.Time
+		}
+	}
+	return partial, nil
+}
+
+// Upgraded counts how many times each package was upgraded since the given
+// time, according to the pacman log at path.
+func Upgraded(path string, since time.Time) (map[string]int, error) {
+	entries, err := ReadLog(path)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, e := range entries {
+		if e.Source != "ALPM" || e.Time.Before(since) {
+			continue
+		}
+		if u, ok := parseUpgraded(e.Message); ok {
+			counts[u.Name]++
+		}
+	}
+	return counts, nil
+}
+
+// SinceUpgrade returns how long ago, as of now, the last full system
+// upgrade recorded in the pacman log at path took place. ok is false when
+// the log records none.
+func SinceUpgrade(path string, now time.Time) (d time.Duration, ok bool, err error) {
+	last, err := LastUpgrade(path)
+	if err != nil || last.IsZero() {
+		return 0, false, err
+	}
+	return now.Sub(last), true, nil
+}
+
+// LastTransaction returns the packages upgraded by the last transaction
+// in the pacman log at path that upgraded any, to review what just changed.
+func LastTransaction(path string) ([]Update, error) {
+	entries, err := ReadLog(path)
+	if err != nil {
+		return nil, err
+	}
+	var last, cur []Update
+	for _, e := range entries {
+		if e.Source != "ALPM" {
+			continue
+		}
+		switch e.Message {
+		case "transaction started":
+			cur = nil
+		case "transaction completed":
+			if len(cur) > 0 {
+				last = cur
+			}
+		default:
+			if u, ok := parseUpgraded(e.Message); ok {
+				cur = append(cur, u)
+			}
+		}
+	}
+	return last, nil
+}
+
+// parseUpgraded parses an ALPM "upgraded foo (1.0-1 -> 1.1-1)" message.
+func parseUpgraded(msg string) (Update, bool) {
+	rest, ok := strings.CutPrefix(msg, "upgraded ")
+	if !ok {
+		return Update{}, false
+	}
+	name, versions, ok := strings.Cut(rest, " (")
+	if !ok {
+		return Update{}, false
+	}
+	older, newer, ok := strings.Cut(strings.TrimSuffix(versions, ")"), " -> ")
+	if !ok {
+		return Update{}, false
+	}
+	return Update{Name: name, Old: older, New: newer}, true
+}
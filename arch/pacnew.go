@@ -0,0 +1,63 @@
+package arch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// pacnewSuffixes are the extensions pacman gives to configuration files it
+// did not overwrite or remove.
+var pacnewSuffixes = []string{".pacnew", ".pacsave"}
+
+func isPacnew(path string) bool {
+	for _, s := range pacnewSuffixes {
+		if strings.HasSuffix(path, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pacdiff returns the pending .pacnew and .pacsave files as listed by
+// pacdiff --output, which searches the pacman database.
+func Pacdiff(ctx context.Context, r runner.Runner) ([]string, error) {
+	out, err := runner.Output(ctx, r, "pacdiff", "--output")
+	if err != nil {
+		return nil, fmt.Errorf("running pacdiff: %w", err)
+	}
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); isPacnew(line) {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// ScanPacnew walks root looking for .pacnew and .pacsave files. It is the
+// fallback when pacman-contrib is not installed; unreadable directories are
+// skipped.
+func ScanPacnew(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() && path != root {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() && isPacnew(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning %s: %w", root, err)
+	}
+	return files, nil
+}
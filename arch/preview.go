@@ -0,0 +1,134 @@
+package arch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// Removal is a package an upgrade would pull in that removes installed
+// ones, either by replacing them or by conflicting with them.
+type Removal struct {
+	Package   string
+	Replaces  []string
+	Conflicts []string
+}
+
+// Conflict is a pair of packages pacman refuses to have installed together.
+type Conflict struct {
+	Package string
+	With    string
+}
+
+// ConflictError reports an upgrade that fails to resolve because of package
+// conflicts, which pacman would only settle by asking to remove With.
+type ConflictError struct {
+	Conflicts []Conflict
+	Err       error
+}
+
+func (e *ConflictError) Error() string {
+	s := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		s[i] = c.Package + " and " + c.With
+	}
+	return fmt.Sprintf("%v: conflicts between %s", e.Err, strings.Join(s, ", "))
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// PreviewUpgrade resolves a full upgrade without performing it, against the
+// sync databases in dbDir (pacman's own when empty), and returns the
+// targets that would remove installed packages. Those usually need manual
+// intervention. An upgrade with unresolvable conflicts fails with a
+// *ConflictError.
+func PreviewUpgrade(ctx context.Context, r runner.Runner, dbDir string) ([]Removal, error) {
+	var db []string
+	if dbDir != "" {
+		db = []string{"--dbpath", dbDir}
+	}
+	args := append(db, "-Sup", "--noconfirm", "--print-format", "%n")
+	out, err := runner.Output(ctx, r, "pacman", args...)
+	targets, err := pacmanLines(args, out, err)
+	if err != nil {
+		var stderr []byte
+		var exitErr *runner.ExitError
+		if errors.As(err, &exitErr) {
+			stderr = exitErr.Stderr
+		}
+		if c := parseConflicts(string(out) + "\n" + string(stderr)); len(c) > 0 {
+			return nil, &ConflictError{Conflicts: c, Err: err}
+		}
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	installed, err := queryPacman(ctx, r, "-Qq")
+	if err != nil {
+		return nil, err
+	}
+	isInstalled := make(map[string]bool, len(installed))
+	for _, n := range installed {
+		isInstalled[n] = true
+	}
+
+	info, err := queryPacman(ctx, r, append(append(db, "-Si"), targets...)...)
+	if err != nil {
+		return nil, err
+	}
+	var removals []Removal
+	for _, pkg := range parseInfo(info) {
+		rm := Removal{
+			Package:   pkg["Name"],
+			Replaces:  installedDeps(pkg["Replaces"], isInstalled, pkg["Name"]),
+			Conflicts: installedDeps(pkg["Conflicts With"], isInstalled, pkg["Name"]),
+		}
+		if len(rm.Replaces) > 0 || len(rm.Conflicts) > 0 {
+			removals = append(removals, rm)
+		}
+	}
+	return removals, nil
+}
+
+// parseConflicts extracts the conflicts pacman asked about, answered with
+// its default no under --noconfirm, from prompts such as
+// ":: foo and bar are in conflict. Remove bar? [y/N]" or, when the conflict
+// comes from a provision, ":: foo and bar are in conflict (baz). Remove bar?".
+func parseConflicts(output string) []Conflict {
+	var conflicts []Conflict
+	seen := make(map[Conflict]bool)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "::"))
+		pair, rest, ok := strings.Cut(line, " are in conflict")
+		if !ok || !strings.Contains(rest, "Remove ") {
+			continue
+		}
+		pkg, with, ok := strings.Cut(pair, " and ")
+		c := Conflict{Package: pkg, With: with}
+		if !ok || seen[c] {
+			continue
+		}
+		seen[c] = true
+		conflicts = append(conflicts, c)
+	}
+	return conflicts
+}
+
+// installedDeps returns the installed packages named in a -Si dependency
+// field such as "foo  bar>=2", skipping self and "None".
+func installedDeps(field string, installed map[string]bool, self string) []string {
+	var names []string
+	for _, dep := range strings.Fields(field) {
+		name := strings.FieldsFunc(dep, func(r rune) bool { return r == '<' || r == '>' || r == '=' })
+		if len(name) > 0 && name[0] != self && installed[name[0]] {
+			names = append(names, name[0])
+		}
+	}
+	return names
+}
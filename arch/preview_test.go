@@ -0,0 +1,64 @@
+package arch
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+const previewCmd = "pacman -Sup --noconfirm --print-format %n"
+
+func TestPreviewUpgrade(t *testing.T) {
+	r := &runner.Fake{Results: map[string]runner.Result{
+		previewCmd:   {Stdout: "pipewire-jack\nvim\n"},
+		"pacman -Qq": {Stdout: "jack2\npipewire\nvim\n"},
+		"pacman -Si pipewire-jack vim": {Stdout: `Repository      : extra
+Name            : pipewire-jack
+Conflicts With  : jack  jack2
+Replaces        : None
+
+Repository      : extra
+Name            : vim
+Conflicts With  : gvim  vim-minimal
+Replaces        : vim-minimal
+`},
+	}}
+	got, err := PreviewUpgrade(context.Background(), r, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Package != "pipewire-jack" || !slices.Equal(got[0].Conflicts, []string{"jack2"}) || got[0].Replaces != nil {
+		t.Errorf("got %+v, want pipewire-jack conflicting with jack2", got)
+	}
+}
+
+func TestPreviewUpgradeConflict(t *testing.T) {
+	r := &runner.Fake{Results: map[string]runner.Result{
+		previewCmd: {
+			Stdout: ":: pipewire-jack and jack2 are in conflict (jack). Remove jack2? [y/N] N\n",
+			Stderr: "error: unresolvable package conflicts detected\nerror: failed to prepare transaction (conflicting dependencies)\n",
+			Code:   1,
+		},
+	}}
+	_, err := PreviewUpgrade(context.Background(), r, "")
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("err = %v, want a *ConflictError", err)
+	}
+	want := []Conflict{{Package: "pipewire-jack", With: "jack2"}}
+	if !slices.Equal(conflict.Conflicts, want) {
+		t.Errorf("conflicts = %+v, want %+v", conflict.Conflicts, want)
+	}
+}
+
+func TestPreviewUpgradeFailure(t *testing.T) {
+	r := &runner.Fake{Results: map[string]runner.Result{
+		previewCmd: {Stderr: "error: failed to init transaction (unable to lock database)\n", Code: 1},
+	}}
+	if _, err := PreviewUpgrade(context.Background(), r, ""); err == nil {
+		t.Error("want the failure of -Sup reported")
+	}
+}
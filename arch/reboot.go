@@ -0,0 +1,180 @@
+package arch
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RebootPackages are the packages whose upgrade only takes full effect after
+// a reboot, besides the kernel itself.
+var RebootPackages = []string{"glibc", "systemd", "dbus", "dbus-broker"}
+
+// RunningKernel returns the release of the running kernel, as uname -r.
+func RunningKernel() (string, error) {
+	b, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return "", fmt.Errorf("reading kernel release: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// BootTime returns when the system was booted.
+func BootTime() (time.Time, error) {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reading boot time: %w", err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if v, ok := strings.CutPrefix(sc.Text(), "btime "); ok {
+			secs, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("parsing boot time: %w", err)
+			}
+			return time.Unix(secs, 0), nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return time.Time{}, fmt.Errorf("reading boot time: %w", err)
+	}
+	return time.Time{}, errors.New("boot time not found in /proc/stat")
+}
+
+// KernelReplaced reports whether the running kernel is no longer installed.
+// Upgrading the kernel package removes the modules of the running release,
+// which is what makes a reboot required. Only a modules directory owned by
+// a kernel package, one holding its pkgbase file, counts: leftovers from
+// DKMS or kernel-modules-hook don't.
+func KernelReplaced() (bool, error) {
+	release, err := RunningKernel()
+	if err != nil {
+		return false, err
+	}
+	installed, err := kernelInstalled(release)
+	return !installed, err
+}
+
+// kernelInstalled reports whether a kernel package provides release.
+func kernelInstalled(release string) (bool, error) {
+	_, err := os.Stat(filepath.Join("/usr/lib/modules", release, "pkgbase"))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// RebootReasons returns why a reboot is required, if it is: "kernel" when the
+// running kernel was replaced, and the name of every RebootPackages entry
+// upgraded since boot according to the pacman log at logPath.
+func RebootReasons(logPath string) ([]string, error) {
+	var reasons []string
+	replaced, err := KernelReplaced()
+	if err != nil {
+		return nil, err
+	}
+	if replaced {
+		reasons = append(reasons, "kernel")
+	}
+
+	boot, err := BootTime()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ReadLog(logPath)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if e.Source != "ALPM" || e.Time.Before(boot) {
+			continue
+		}
+		u, ok := parseUpgraded(e.Message)
+		if !ok || seen[u.Name] {
+			continue
+		}
+		for _, p := range RebootPackages {
+			if p == u.Name {
+				seen[u.Name] = true
+				reasons = append(reasons, u.Name)
+			}
+		}
+	}
+	return reasons, nil
+}
+
+// KernelMismatch describes a running kernel that differs from the one
+// installed for its flavor.
+type KernelMismatch struct {
+	// Flavor is the kernel package, such as linux, linux-lts or linux-zen.
+	Flavor    string
+	Running   string
+	Installed string // empty when the flavor was removed
+}
+
+// InstalledKernelPackages maps each installed kernel package to its
+// release, read from the pkgbase files in /usr/lib/modules.
+func InstalledKernelPackages() (map[string]string, error) {
+	releases, err := InstalledKernels()
+	if err != nil {
+		return nil, err
+	}
+	pkgs := make(map[string]string, len(releases))
+	for _, rel := range releases {
+		b, err := os.ReadFile(filepath.Join("/usr/lib/modules", rel, "pkgbase"))
+		if err != nil {
+			return nil, fmt.Errorf("reading kernel pkgbase: %w", err)
+		}
+		pkgs[strings.TrimSpace(string(b))] = rel
+	}
+	return pkgs, nil
+}
+
+// kernelFlavor returns the kernel package a release belongs to. Arch
+// kernels end their release in the flavor, as in "6.6.30-1-lts" or
+// "6.9.1-zen1-1-zen", so the installed package with the longest such
+// suffix wins. Without a match the flavor is guessed from the official
+// kernels, linux when nothing fits.
+func kernelFlavor(release string, installed map[string]string) string {
+	flavor := ""
+	for pkg := range installed {
+		suffix := strings.TrimPrefix(pkg, "linux-")
+		if pkg != "linux" && strings.HasSuffix(release, "-"+suffix) && len(pkg) > len(flavor) {
+			flavor = pkg
+		}
+	}
+	if flavor != "" {
+		return flavor
+	}
+	for _, f := range []string{"rt-lts", "lts", "zen", "hardened", "rt"} {
+		if strings.HasSuffix(release, "-"+f) {
+			return "linux-" + f
+		}
+	}
+	return "linux"
+}
+
+// RunningKernelMismatch compares the running kernel with the installed
+// kernels. It returns nil when the running release is still installed.
+func RunningKernelMismatch() (*KernelMismatch, error) {
+	running, err := RunningKernel()
+	if err != nil {
+		return nil, err
+	}
+	if installed, err := kernelInstalled(running); err != nil || installed {
+		return nil, err
+	}
+	pkgs, err := InstalledKernelPackages()
+	if err != nil {
+		return nil, err
+	}
+	flavor := kernelFlavor(running, pkgs)
+	return &KernelMismatch{Flavor: flavor, Running: running, Installed: pkgs[flavor]}, nil
+}
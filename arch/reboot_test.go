@@ -0,0 +1,28 @@
+package arch
+
+import "testing"
+
+func TestKernelFlavor(t *testing.T) {
+	installed := map[string]string{
+		"linux":         "6.9.2.arch1-1",
+		"linux-lts":     "6.6.31-1-lts",
+		"linux-rt-lts":  "6.6.30-1-rt-lts",
+		"linux-cachyos": "6.9.2-1-cachyos",
+	}
+	tests := []struct {
+		release string
+		want    string
+	}{
+		{"6.9.1-arch1-1", "linux"},
+		{"6.6.30-1-lts", "linux-lts"},
+		{"6.6.29-1-rt-lts", "linux-rt-lts"},
+		{"6.9.1-2-cachyos", "linux-cachyos"},
+		{"6.9.1-zen1-1-zen", "linux-zen"},
+		{"6.9.1-1-custom", "linux"},
+	}
+	for _, tt := range tests {
+		if got := kernelFlavor(tt.release, installed); got != tt.want {
+			t.Errorf("kernelFlavor(%q) = %q, want %q", tt.release, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package arch
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PacmanConf is the default pacman configuration file.
+const PacmanConf = "/etc/pacman.conf"
+
+// OfficialRepos are the repositories maintained by Arch Linux.
+var OfficialRepos = []string{
+	"core", "extra", "multilib",
+	"core-testing", "extra-testing", "multilib-testing",
+	"gnome-unstable", "kde-unstable",
+}
+
+// ThirdPartyRepos returns the repositories configured in the pacman.conf at
+// path that are not official, such as chaotic-aur, ALHP or cachyos.
+func ThirdPartyRepos(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading pacman.conf: %w", err)
+	}
+	defer f.Close()
+	official := make(map[string]bool, len(OfficialRepos))
+	for _, r := range OfficialRepos {
+		official[r] = true
+	}
+	var repos []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+			continue
+		}
+		if name := line[1 : len(line)-1]; name != "options" && !official[name] {
+			repos = append(repos, name)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading pacman.conf: %w", err)
+	}
+	return repos, nil
+}
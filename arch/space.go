@@ -0,0 +1,161 @@
+package arch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// FreeSpace returns the bytes available to unprivileged users on the
+// filesystem holding path.
+func FreeSpace(path string) (int64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, fmt.Errorf("checking free space on %s: %w", path, err)
+	}
+	return int64(st.Bavail) * int64(st.Frsize), nil
+}
+
+// UpgradeSpace estimates the disk space the updates need: the size of the
+// packages to download, and how much the installed size grows. The sync
+// databases in dbDir are used, pacman's own when empty. updates must only
+// hold repo packages: pacman fails on the names of AUR or other foreign
+// packages, and so does UpgradeSpace.
+func UpgradeSpace(ctx context.Context, r runner.Runner, dbDir string, updates []Update) (download, growth int64, err error) {
+	if len(updates) == 0 {
+		return 0, 0, nil
+	}
+	names := make([]string, len(updates))
+	for i, u := range updates {
+		names[i] = u.Name
+	}
+	var db []string
+	if dbDir != "" {
+		db = []string{"--dbpath", dbDir}
+	}
+	sync, err := queryPacman(ctx, r, append(append(db, "-Si"), names...)...)
+	if err != nil {
+		return 0, 0, err
+	}
+	local, err := queryPacman(ctx, r, append([]string{"-Qi"}, names...)...)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, pkg := range parseInfo(sync) {
+		download += parseSize(pkg["Download Size"])
+		growth += parseSize(pkg["Installed Size"])
+	}
+	for _, pkg := range parseInfo(local) {
+		growth -= parseSize(pkg["Installed Size"])
+	}
+	return download, growth, nil
+}
+
+// BootDir is where the kernel and initramfs images are installed.
+const BootDir = "/boot"
+
+// LowSpace returns the mount points on which the updates likely don't fit:
+// "/", where both the package cache and the installed files live, and
+// BootDir when it is a separate filesystem short on room for the images of
+// upgraded kernels.
+func LowSpace(ctx context.Context, r runner.Runner, dbDir string, updates []Update) ([]string, error) {
+	download, growth, err := UpgradeSpace(ctx, r, dbDir, updates)
+	if err != nil {
+		return nil, err
+	}
+	var short []string
+	free, err := FreeSpace("/")
+	if err != nil {
+		return nil, err
+	}
+	if download+max(growth, 0) > free {
+		short = append(short, "/")
+	}
+	need, err := bootSpace(updates)
+	if err != nil || need == 0 {
+		return short, err
+	}
+	free, err = FreeSpace(BootDir)
+	if err != nil {
+		return nil, err
+	}
+	if need > free {
+		short = append(short, BootDir)
+	}
+	return short, nil
+}
+
+// bootSpace returns the free space a separate BootDir needs for the kernel
+// updates: the size of their current images, as hooks such as mkinitcpio's
+// may write the new ones before the old are gone. It is zero when BootDir
+// is on the root filesystem.
+func bootSpace(updates []Update) (int64, error) {
+	separate, err := separateMount(BootDir)
+	if err != nil || !separate {
+		return 0, err
+	}
+	kernels, err := InstalledKernelPackages()
+	if err != nil {
+		return 0, err
+	}
+	var need int64
+	for _, u := range updates {
+		if _, ok := kernels[u.Name]; !ok {
+			continue
+		}
+		for _, f := range []string{"vmlinuz-" + u.Name, "initramfs-" + u.Name + ".img", "initramfs-" + u.Name + "-fallback.img"} {
+			if fi, err := os.Stat(filepath.Join(BootDir, f)); err == nil {
+				need += fi.Size()
+			}
+		}
+	}
+	return need, nil
+}
+
+// separateMount reports whether path exists on a filesystem other than
+// the root one.
+func separateMount(path string) (bool, error) {
+	fi, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	root, err := os.Stat("/")
+	if err != nil {
+		return false, err
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	rst, rok := root.Sys().(*syscall.Stat_t)
+	return ok && rok && st.Dev != rst.Dev, nil
+}
+
+var sizeUnits = map[string]float64{
+	"B":   1,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"TiB": 1 << 40,
+}
+
+// parseSize parses sizes like "12.34 MiB" as pacman prints them. Anything
+// unparseable counts as zero.
+func parseSize(s string) int64 {
+	num, unit, ok := strings.Cut(s, " ")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(num, ",", "."), 64)
+	if err != nil {
+		return 0
+	}
+	return int64(n * sizeUnits[unit])
+}
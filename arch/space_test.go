@@ -0,0 +1,47 @@
+package arch
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+func TestUpgradeSpace(t *testing.T) {
+	r := &runner.Fake{Results: map[string]runner.Result{
+		"pacman -Si linux vim": {Stdout: `Name            : linux
+Download Size   : 140.50 MiB
+Installed Size  : 140.00 MiB
+
+Name            : vim
+Download Size   : 2.00 MiB
+Installed Size  : 4,00 MiB
+`},
+		"pacman -Qi linux vim": {Stdout: `Name            : linux
+Installed Size  : 138.00 MiB
+
+Name            : vim
+Installed Size  : 5.00 MiB
+`},
+	}}
+	updates := []Update{{Name: "linux"}, {Name: "vim"}}
+	download, growth, err := UpgradeSpace(context.Background(), r, "", updates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(142.5 * (1 << 20)); download != want {
+		t.Errorf("download = %d, want %d", download, want)
+	}
+	if want := int64(1 << 20); growth != want {
+		t.Errorf("growth = %d, want %d", growth, want)
+	}
+}
+
+func TestUpgradeSpaceForeign(t *testing.T) {
+	r := &runner.Fake{Results: map[string]runner.Result{
+		"pacman -Si yay": {Stderr: "error: package 'yay' was not found\n", Code: 1},
+	}}
+	if _, _, err := UpgradeSpace(context.Background(), r, "", []Update{{Name: "yay"}}); err == nil {
+		t.Error("want an error for a package missing from the sync databases")
+	}
+}
@@ -0,0 +1,63 @@
+package arch
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// SyncDB is a private copy of the sync databases for checkupdates, so
+// several users or instances on one machine don't share the default
+// location in /tmp.
+type SyncDB struct {
+	Dir   string
+	owned bool
+}
+
+// OpenSyncDB prepares dir as the private database directory, creating it
+// with owner-only permissions if needed. An existing directory must belong
+// to the current user; its permissions are restricted to the owner. An
+// empty dir creates a temporary one that Close removes.
+func OpenSyncDB(dir string) (*SyncDB, error) {
+	if dir == "" {
+		tmp, err := os.MkdirTemp("", "waybar-updates-btw-db-")
+		if err != nil {
+			return nil, fmt.Errorf("creating sync db: %w", err)
+		}
+		return &SyncDB{Dir: tmp, owned: true}, nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating sync db: %w", err)
+	}
+	// The directory may have existed already, possibly created by someone
+	// else in a shared place like /tmp.
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("checking sync db: %w", err)
+	}
+	if st, ok := fi.Sys().(*syscall.Stat_t); !ok || int(st.Uid) != os.Getuid() {
+		return nil, fmt.Errorf("sync db %s is not owned by the current user", dir)
+	}
+	if fi.Mode().Perm() != 0o700 {
+		if err := os.Chmod(dir, 0o700); err != nil {
+			return nil, fmt.Errorf("restricting sync db permissions: %w", err)
+		}
+	}
+	return &SyncDB{Dir: dir}, nil
+}
+
+// CheckUpdates runs checkupdates against this database, see CheckUpdates.
+func (db *SyncDB) CheckUpdates(ctx context.Context, r runner.Runner, download bool) ([]Update, error) {
+	return checkUpdates(ctx, r, db.Dir, download)
+}
+
+// Close removes the database directory if OpenSyncDB created it.
+func (db *SyncDB) Close() error {
+	if !db.owned {
+		return nil
+	}
+	return os.RemoveAll(db.Dir)
+}
@@ -0,0 +1,48 @@
+package arch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// AURHelpers are the AUR helpers UpgradeCommand looks for, in order of
+// preference.
+var AURHelpers = []string{"paru", "yay", "pikaur", "trizen"}
+
+// UpgradeCommand returns the command that upgrades the system. A non-empty
+// override is used as is; otherwise the first installed AUR helper upgrades
+// both repo and AUR packages, falling back to pacman.
+func UpgradeCommand(r runner.Runner, override string) []string {
+	if args := strings.Fields(override); len(args) > 0 {
+		return args
+	}
+	for _, h := range AURHelpers {
+		if _, err := r.LookPath(h); err == nil {
+			return []string{h, "-Syu"}
+		}
+	}
+	return []string{"sudo", "pacman", "-Syu"}
+}
+
+// Launch runs command inside terminal, a command line such as "foot -e" or
+// "kitty --hold", and waits for the terminal to exit.
+func Launch(ctx context.Context, terminal string, command []string) error {
+	args := append(strings.Fields(terminal), command...)
+	if len(args) == len(command) {
+		return errors.New("no terminal configured")
+	}
+	// Stdout is left alone: in a waybar module it carries the JSON stream,
+	// which the terminal emulator's chatter would corrupt.
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running %s: %w", args[0], err)
+	}
+	return nil
+}
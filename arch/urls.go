@@ -0,0 +1,10 @@
+package arch
+
+import "net/url"
+
+// PackageURL returns the archweb page of an official package. arch is the
+// package architecture, "x86_64" or "any".
+func PackageURL(repo, arch, name string) string {
+	return "https://archlinux.org/packages/" + url.PathEscape(repo) + "/" +
+		url.PathEscape(arch) + "/" + url.PathEscape(name) + "/"
+}
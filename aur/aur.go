@@ -0,0 +1,220 @@
+// Package aur queries the Arch User Repository RPC interface.
+package aur
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+	"github.com/lmcanavals/waybar-updates-btw/version"
+)
+
+// DefaultURL is the RPC endpoint of aur.archlinux.org.
+const DefaultURL = "https://aur.archlinux.org/rpc/v5"
+
+// workers bounds how many info requests run at once.
+const workers = 4
+
+// maxArgs bounds the number of packages per info request, keeping the query
+// string well below aurweb's URI length limit.
+const maxArgs = 100
+
+// Package is the metadata the RPC returns for a package.
+type Package struct {
+	Name    string `json:"Name"`
+	Version string `json:"Version"`
+	// URL is the upstream project page.
+	URL string `json:"URL"`
+	// OutOfDate is the unix time the package was flagged out-of-date, nil
+	// when it is not flagged.
+	OutOfDate *int64 `json:"OutOfDate"`
+	// Maintainer is empty for orphaned packages.
+	Maintainer string  `json:"Maintainer"`
+	NumVotes   int     `json:"NumVotes"`
+	Popularity float64 `json:"Popularity"`
+	// LastModified is the unix time the PKGBUILD was last updated.
+	LastModified int64 `json:"LastModified"`
+}
+
+// Flagged reports whether the package is flagged out-of-date.
+func (p Package) Flagged() bool {
+	return p.OutOfDate != nil
+}
+
+// FlaggedAt returns when the package was flagged out-of-date, or the zero
+// time when it is not.
+func (p Package) FlaggedAt() time.Time {
+	if p.OutOfDate == nil {
+		return time.Time{}
+	}
+	return time.Unix(*p.OutOfDate, 0)
+}
+
+// Modified returns when the package was last updated on the AUR.
+func (p Package) Modified() time.Time {
+	return time.Unix(p.LastModified, 0)
+}
+
+// Client talks to an aurweb RPC endpoint.
+type Client struct {
+	// BaseURL is the RPC endpoint, DefaultURL when empty.
+	BaseURL string
+	// HTTP is the client used for requests, http.DefaultClient when nil.
+	HTTP runner.Doer
+	// Timeout bounds a whole Info call, all of its requests included. Zero
+	// means no limit besides the context.
+	Timeout time.Duration
+}
+
+type response struct {
+	Type    string    `json:"type"`
+	Error   string    `json:"error"`
+	Results []Package `json:"results"`
+}
+
+// Info returns the metadata of the named packages. Names unknown to the AUR
+// are missing from the result. Large queries are split into chunks fetched
+// concurrently; when some of them fail, the packages from the others are
+// returned together with the error.
+func (c *Client) Info(ctx context.Context, names []string) ([]Package, error) {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	var chunks [][]string
+	for start := 0; start < len(names); start += maxArgs {
+		chunks = append(chunks, names[start:min(start+maxArgs, len(names))])
+	}
+
+	var (
+		mu   sync.Mutex
+		pkgs []Package
+		errs []error
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, workers)
+	)
+	for _, chunk := range chunks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res, err := c.info(ctx, chunk)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			pkgs = append(pkgs, res...)
+		}()
+	}
+	wg.Wait()
+	return pkgs, errors.Join(errs...)
+}
+
+func (c *Client) info(ctx context.Context, names []string) ([]Package, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultURL
+	}
+	q := url.Values{"arg[]": names}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+"/info?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	var hc runner.Doer = http.DefaultClient
+	if c.HTTP != nil {
+		hc = c.HTTP
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying aur: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var r response
+	if resp.StatusCode != http.StatusOK {
+		// aurweb explains rejected requests in a JSON body, but gateways
+		// and proxies answer with HTML pages.
+		if json.NewDecoder(resp.Body).Decode(&r) == nil && r.Type == "error" {
+			return nil, fmt.Errorf("querying aur: %s: %s", resp.Status, r.Error)
+		}
+		return nil, fmt.Errorf("querying aur: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("decoding aur response: %w", err)
+	}
+	if r.Type == "error" {
+		return nil, errors.New("aur: " + r.Error)
+	}
+	return r.Results, nil
+}
+
+// Flagged returns the packages flagged out-of-date, whether or not a newer
+// version has been published yet.
+func Flagged(pkgs []Package) []Package {
+	var flagged []Package
+	for _, p := range pkgs {
+		if p.Flagged() {
+			flagged = append(flagged, p)
+		}
+	}
+	return flagged
+}
+
+// Orphaned returns the packages that have no maintainer.
+func Orphaned(pkgs []Package) []Package {
+	var orphaned []Package
+	for _, p := range pkgs {
+		if p.Maintainer == "" {
+			orphaned = append(orphaned, p)
+		}
+	}
+	return orphaned
+}
+
+// PackageURL returns the aurweb page of the named package.
+func PackageURL(name string) string {
+	return "https://aur.archlinux.org/packages/" + url.PathEscape(name)
+}
+
+// Newer returns the AUR version of the named package when it is newer than
+// current, for instance to tell whether this program itself has a release
+// ready; ok is false otherwise.
+func (c *Client) Newer(ctx context.Context, name, current string) (latest string, ok bool, err error) {
+	pkgs, err := c.Info(ctx, []string{name})
+	if err != nil {
+		return "", false, err
+	}
+	if len(pkgs) == 0 {
+		return "", false, fmt.Errorf("aur: package %s not found", name)
+	}
+	latest = pkgs[0].Version
+	return latest, version.Compare(latest, current) > 0, nil
+}
+
+// Missing returns the names the AUR gave no result for among those queried:
+// packages that were renamed, deleted or built locally, and so will never
+// receive updates through it.
+func Missing(names []string, pkgs []Package) []string {
+	found := make(map[string]bool, len(pkgs))
+	for _, p := range pkgs {
+		found[p.Name] = true
+	}
+	var missing []string
+	for _, n := range names {
+		if !found[n] {
+			missing = append(missing, n)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,88 @@
+package aur
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// infoResponse answers an info request for names with a package for each.
+func infoResponse(t *testing.T, names []string) (string, runner.Response) {
+	t.Helper()
+	res := response{Type: "multiple"}
+	for _, n := range names {
+		res.Results = append(res.Results, Package{Name: n, Version: "1.0-1"})
+	}
+	b, err := json.Marshal(res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return DefaultURL + "/info?" + url.Values{"arg[]": names}.Encode(), runner.Response{Body: string(b)}
+}
+
+func TestInfo(t *testing.T) {
+	names := make([]string, 2*maxArgs+50)
+	for i := range names {
+		names[i] = fmt.Sprintf("pkg%03d", i)
+	}
+	chunks := [][]string{names[:maxArgs], names[maxArgs : 2*maxArgs], names[2*maxArgs:]}
+
+	tests := []struct {
+		name    string
+		answer  []bool // whether each chunk gets a response
+		want    int
+		wantErr bool
+	}{
+		{"all chunks", []bool{true, true, true}, len(names), false},
+		{"failed chunk", []bool{true, false, true}, len(names) - maxArgs, true},
+		{"all failed", []bool{false, false, false}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doer := &runner.FakeDoer{Responses: make(map[string]runner.Response)}
+			for i, chunk := range chunks {
+				if tt.answer[i] {
+					u, res := infoResponse(t, chunk)
+					doer.Responses[u] = res
+				}
+			}
+			c := &Client{HTTP: doer}
+			pkgs, err := c.Info(context.Background(), names)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if len(pkgs) != tt.want {
+				t.Errorf("got %d packages, want %d", len(pkgs), tt.want)
+			}
+			if n := len(doer.Requests()); n != len(chunks) {
+				t.Errorf("made %d requests, want %d", n, len(chunks))
+			}
+		})
+	}
+}
+
+func TestInfoError(t *testing.T) {
+	tests := []struct {
+		name string
+		res  runner.Response
+		want string
+	}{
+		{"error body", runner.Response{Body: `{"type":"error","error":"Too many package arguments."}`}, "aur: Too many package arguments."},
+		{"error status", runner.Response{Status: 429, Body: `{"type":"error","error":"Rate limit reached"}`}, "querying aur: 429 Too Many Requests: Rate limit reached"},
+		{"html page", runner.Response{Status: 502, Body: "<html><body>Bad Gateway</body></html>"}, "querying aur: 502 Bad Gateway"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doer := &runner.FakeDoer{Responses: map[string]runner.Response{DefaultURL + "/info": tt.res}}
+			c := &Client{HTTP: doer}
+			_, err := c.Info(context.Background(), []string{"yay"})
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
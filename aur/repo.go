@@ -0,0 +1,46 @@
+package aur
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/arch"
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+	"github.com/lmcanavals/waybar-updates-btw/version"
+)
+
+// RepoRebuilds compares the packages of an aurutils local repository
+// against the AUR and returns those with a newer version upstream, which
+// need rebuilding. An empty repo lets aurutils pick its default.
+func (c *Client) RepoRebuilds(ctx context.Context, r runner.Runner, repo string) ([]arch.Update, error) {
+	args := []string{"repo", "--list"}
+	if repo != "" {
+		args = append(args, "--database", repo)
+	}
+	out, err := runner.Output(ctx, r, "aur", args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing aurutils repo: %w", err)
+	}
+	local := make(map[string]string)
+	var names []string
+	for _, line := range strings.Split(string(out), "\n") {
+		// "name\tversion"
+		if f := strings.Fields(line); len(f) >= 2 {
+			local[f[0]] = f[1]
+			names = append(names, f[0])
+		}
+	}
+
+	pkgs, err := c.Info(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	var rebuilds []arch.Update
+	for _, p := range pkgs {
+		if old, ok := local[p.Name]; ok && version.Compare(p.Version, old) > 0 {
+			rebuilds = append(rebuilds, arch.Update{Name: p.Name, Old: old, New: p.Version})
+		}
+	}
+	return rebuilds, nil
+}
@@ -0,0 +1,70 @@
+package aur
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// TLSOptions adjusts how HTTPS connections are verified and authenticated,
+// for TLS-intercepting proxies and self-hosted aurweb instances.
+type TLSOptions struct {
+	// CACert is a PEM file of certificates trusted in addition to the
+	// system roots.
+	CACert string
+	// ClientCert and ClientKey are PEM files for client authentication.
+	ClientCert string
+	ClientKey  string
+	// MinVersion is the lowest accepted TLS version, "1.2" or "1.3". Older
+	// versions are rejected, as Go clients already refuse them by default.
+	MinVersion string
+}
+
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// HTTPClient returns an HTTP client configured with opts, suitable for
+// Client.HTTP.
+func HTTPClient(opts TLSOptions) (*http.Client, error) {
+	cfg := &tls.Config{}
+	if opts.CACert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(opts.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA certificate: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", opts.CACert)
+		}
+		cfg.RootCAs = pool
+	}
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		if opts.ClientCert == "" || opts.ClientKey == "" {
+			return nil, errors.New("client certificate and key must be given together")
+		}
+		cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if opts.MinVersion != "" {
+		v, ok := tlsVersions[opts.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS version %q: want 1.2 or 1.3", opts.MinVersion)
+		}
+		cfg.MinVersion = v
+	}
+
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.TLSClientConfig = cfg
+	return &http.Client{Transport: tr}, nil
+}
@@ -0,0 +1,34 @@
+package aur
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+)
+
+func TestHTTPClientMinVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    uint16
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"1.2", tls.VersionTLS12, false},
+		{"1.3", tls.VersionTLS13, false},
+		{"1.0", 0, true},
+		{"1.1", 0, true},
+	}
+	for _, tt := range tests {
+		c, err := HTTPClient(TLSOptions{MinVersion: tt.in})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("HTTPClient(%q): err = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := c.Transport.(*http.Transport).TLSClientConfig.MinVersion; got != tt.want {
+			t.Errorf("HTTPClient(%q): MinVersion = %#x, want %#x", tt.in, got, tt.want)
+		}
+	}
+}
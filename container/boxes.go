@@ -0,0 +1,93 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// countScript prints how many updates the package manager found inside a
+// container, whichever distribution it runs.
+const countScript = `{
+if command -v checkupdates >/dev/null; then checkupdates
+elif command -v dnf >/dev/null; then dnf -q check-update | grep '\.'
+elif command -v apt >/dev/null; then apt list --upgradable 2>/dev/null | tail -n +2
+elif command -v zypper >/dev/null; then zypper -q lu | grep '^v '
+elif command -v apk >/dev/null; then apk -q version -l '<'
+fi
+} 2>/dev/null | grep -c . || true`
+
+// Box is a distrobox or toolbox container.
+type Box struct {
+	Name string
+	// Tool is the command managing the container, "distrobox" or
+	// "toolbox".
+	Tool string
+}
+
+// Boxes lists the distrobox and toolbox containers of the user. Tools that
+// are not installed are ignored.
+func Boxes(ctx context.Context, r runner.Runner) ([]Box, error) {
+	var boxes []Box
+	if _, err := r.LookPath("distrobox"); err == nil {
+		out, err := runner.Output(ctx, r, "distrobox", "list", "--no-color")
+		if err != nil {
+			return nil, fmt.Errorf("listing distrobox containers: %w", err)
+		}
+		// "ID | NAME | STATUS | IMAGE" after a header line.
+		for _, line := range tail(out) {
+			if f := strings.Split(line, "|"); len(f) >= 2 {
+				boxes = append(boxes, Box{Name: strings.TrimSpace(f[1]), Tool: "distrobox"})
+			}
+		}
+	}
+	if _, err := r.LookPath("toolbox"); err == nil {
+		out, err := runner.Output(ctx, r, "toolbox", "list", "--containers")
+		if err != nil {
+			return nil, fmt.Errorf("listing toolbox containers: %w", err)
+		}
+		// "CONTAINER ID  CONTAINER NAME  CREATED  STATUS  IMAGE NAME"
+		for _, line := range tail(out) {
+			if f := strings.Fields(line); len(f) >= 2 {
+				boxes = append(boxes, Box{Name: f[1], Tool: "toolbox"})
+			}
+		}
+	}
+	return boxes, nil
+}
+
+// tail returns the non-empty lines of out after its header.
+func tail(out []byte) []string {
+	var lines []string
+	for i, line := range strings.Split(string(out), "\n") {
+		if i > 0 && strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Count returns the number of pending updates inside b. Stopped
+// containers are started to run the check.
+func (b Box) Count(ctx context.Context, r runner.Runner) (int, error) {
+	cmd := runner.Command{Name: "distrobox", Args: []string{"enter", b.Name, "--", "sh", "-c", countScript}}
+	if b.Tool == "toolbox" {
+		cmd = runner.Command{Name: "toolbox", Args: []string{"run", "-c", b.Name, "sh", "-c", countScript}}
+	}
+	out, err := r.Output(ctx, cmd)
+	if err != nil {
+		return 0, fmt.Errorf("checking container %s: %w", b.Name, err)
+	}
+	f := strings.Fields(string(out))
+	if len(f) == 0 {
+		return 0, fmt.Errorf("checking container %s: no output", b.Name)
+	}
+	n, err := strconv.Atoi(f[len(f)-1])
+	if err != nil {
+		return 0, fmt.Errorf("checking container %s: %w", b.Name, err)
+	}
+	return n, nil
+}
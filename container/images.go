@@ -0,0 +1,71 @@
+// Package container checks container images and containers for updates.
+package container
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// Image is a locally pulled image whose tag points elsewhere upstream.
+type Image struct {
+	Ref    string // repository:tag
+	Local  string // digest of the pulled manifest
+	Remote string // digest the tag points to in the registry
+}
+
+// Outdated returns the images pulled with engine, "podman" or "docker",
+// whose tag now points to a different manifest in the registry. The
+// registry is queried with skopeo. Images without one, built locally or
+// under localhost/, are skipped; when inspecting others fails, the images
+// checked are returned together with the errors.
+func Outdated(ctx context.Context, r runner.Runner, engine string) ([]Image, error) {
+	out, err := runner.Output(ctx, r, engine, "images", "--digests",
+		"--format", "{{.Repository}}:{{.Tag}} {{.Digest}}")
+	if err != nil {
+		return nil, fmt.Errorf("listing %s images: %w", engine, err)
+	}
+	var (
+		outdated []Image
+		errs     []error
+	)
+	for _, line := range strings.Split(string(out), "\n") {
+		ref, local, ok := strings.Cut(strings.TrimSpace(line), " ")
+		if !ok || strings.Contains(ref, "<none>") || !strings.HasPrefix(local, "sha256:") || localhost(ref) {
+			continue
+		}
+		remote, err := remoteDigest(ctx, r, ref)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if remote != local {
+			outdated = append(outdated, Image{Ref: ref, Local: local, Remote: remote})
+		}
+	}
+	return outdated, errors.Join(errs...)
+}
+
+// localhost reports whether ref names an image podman tagged as local,
+// which no registry serves.
+func localhost(ref string) bool {
+	return strings.HasPrefix(ref, "localhost/") || strings.HasPrefix(ref, "localhost:")
+}
+
+// remoteDigest returns the digest of the manifest ref points to. The raw
+// manifest is hashed rather than asking skopeo for its digest, which would
+// resolve multi-arch lists to a single platform and never match the local
+// repo digest.
+func remoteDigest(ctx context.Context, r runner.Runner, ref string) (string, error) {
+	raw, err := runner.Output(ctx, r, "skopeo", "inspect", "--raw", "docker://"+ref)
+	if err != nil {
+		return "", fmt.Errorf("inspecting %s: %w", ref, err)
+	}
+	sum := sha256.Sum256(raw)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,40 @@
+package container
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+func digest(manifest string) string {
+	sum := sha256.Sum256([]byte(manifest))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestOutdated(t *testing.T) {
+	const images = "podman images --digests --format {{.Repository}}:{{.Tag}} {{.Digest}}"
+	r := &runner.Fake{Results: map[string]runner.Result{
+		images: {Stdout: "docker.io/library/alpine:latest " + digest("alpine old") + "\n" +
+			"docker.io/library/nginx:latest " + digest("nginx") + "\n" +
+			"localhost/app:latest " + digest("app") + "\n" +
+			"ghcr.io/private/tool:1 " + digest("tool") + "\n"},
+		"skopeo inspect --raw docker://docker.io/library/alpine:latest": {Stdout: "alpine new"},
+		"skopeo inspect --raw docker://docker.io/library/nginx:latest":  {Stdout: "nginx"},
+		"skopeo inspect --raw docker://ghcr.io/private/tool:1":          {Code: 1, Stderr: "unauthorized"},
+	}}
+	got, err := Outdated(context.Background(), r, "podman")
+	if err == nil {
+		t.Error("want the failed inspection of ghcr.io/private/tool:1 reported")
+	}
+	if len(got) != 1 || got[0].Ref != "docker.io/library/alpine:latest" || got[0].Remote != digest("alpine new") {
+		t.Errorf("got %+v, want only alpine outdated", got)
+	}
+	for _, c := range r.Calls() {
+		if c.String() == "skopeo inspect --raw docker://localhost/app:latest" {
+			t.Error("inspected a localhost image")
+		}
+	}
+}
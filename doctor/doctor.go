@@ -0,0 +1,136 @@
+// Package doctor diagnoses the environment the update checks depend on.
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/arch"
+	"github.com/lmcanavals/waybar-updates-btw/aur"
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// DBLock is the lock pacman holds while a transaction runs.
+const DBLock = "/var/lib/pacman/db.lck"
+
+// Finding is the result of one diagnostic.
+type Finding struct {
+	Check string
+	OK    bool
+	// Detail describes what was found.
+	Detail string
+	// Hint suggests how to fix a failed check.
+	Hint string
+}
+
+func (f Finding) String() string {
+	mark := "ok"
+	if !f.OK {
+		mark = "FAIL"
+	}
+	s := fmt.Sprintf("[%s] %s: %s", mark, f.Check, f.Detail)
+	if !f.OK && f.Hint != "" {
+		s += "\n       " + f.Hint
+	}
+	return s
+}
+
+// Run performs every diagnostic, running commands with r and HTTP requests
+// with client.
+func Run(ctx context.Context, r runner.Runner, client runner.Doer) []Finding {
+	return []Finding{
+		checkContrib(ctx, r),
+		checkLock(),
+		checkMirror(ctx, client),
+		checkAurweb(ctx, client),
+	}
+}
+
+func checkContrib(ctx context.Context, r runner.Runner) Finding {
+	f := Finding{Check: "checkupdates", Hint: "install pacman-contrib"}
+	path, err := r.LookPath("checkupdates")
+	if err != nil {
+		f.Detail = "not found in PATH"
+		return f
+	}
+	out, err := runner.Output(ctx, r, "pacman", "-Q", "pacman-contrib")
+	if err != nil {
+		f.OK, f.Detail = true, path+" (not from pacman-contrib)"
+		return f
+	}
+	f.OK, f.Detail = true, path+", "+strings.TrimSpace(string(out))
+	return f
+}
+
+func checkLock() Finding {
+	f := Finding{Check: "database lock"}
+	_, err := os.Stat(DBLock)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		f.OK, f.Detail = true, "not locked"
+	case err != nil:
+		f.Detail = err.Error()
+	default:
+		f.Detail = DBLock + " exists"
+		f.Hint = "wait for the running transaction, or remove the file if no pacman is running"
+	}
+	return f
+}
+
+func checkMirror(ctx context.Context, client runner.Doer) Finding {
+	base, err := arch.FirstMirror(arch.Mirrorlist)
+	if err != nil {
+		return Finding{Check: "mirror", Detail: err.Error(), Hint: "configure a Server in " + arch.Mirrorlist}
+	}
+	url := base + "/lastupdate"
+	f := checkReachable(ctx, client, "mirror", url)
+	if f.OK && f.status != http.StatusOK {
+		// The host answers but has no lastupdate where the Server line
+		// points: the path is wrong, or it is no Arch mirror.
+		f.OK = false
+		f.Detail = fmt.Sprintf("%s answered %d %s", url, f.status, http.StatusText(f.status))
+		f.Hint = "check the Server path in " + arch.Mirrorlist
+	}
+	return f.Finding
+}
+
+// reachability is a Finding along with the HTTP status received.
+type reachability struct {
+	Finding
+	status int
+}
+
+// checkAurweb treats any non-5xx answer as aurweb being up, as the info
+// endpoint may reject requests without arguments.
+func checkAurweb(ctx context.Context, client runner.Doer) Finding {
+	return checkReachable(ctx, client, "aurweb", aur.DefaultURL+"/info").Finding
+}
+
+// checkReachable requests url, failing on network errors and server-side
+// (5xx) errors.
+func checkReachable(ctx context.Context, client runner.Doer, name, url string) reachability {
+	f := reachability{Finding: Finding{Check: name, Hint: "check the network connection and proxy settings"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		f.Detail = err.Error()
+		return f
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		f.Detail = err.Error()
+		return f
+	}
+	resp.Body.Close()
+	f.status = resp.StatusCode
+	if resp.StatusCode >= 500 {
+		f.Detail = url + " answered " + resp.Status
+		f.Hint = "the server is having trouble, try again later"
+		return f
+	}
+	f.OK, f.Detail = true, url+" reachable ("+resp.Status+")"
+	return f
+}
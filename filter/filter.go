@@ -0,0 +1,142 @@
+// Package filter selects which pending updates are counted and shown.
+package filter
+
+import (
+	"path"
+
+	"github.com/lmcanavals/waybar-updates-btw/arch"
+	"github.com/lmcanavals/waybar-updates-btw/version"
+)
+
+// Override forces the severity of the packages matching a shell pattern,
+// for instance treating linux* as major or *-git as other.
+type Override struct {
+	Pattern  string
+	Severity version.Severity
+}
+
+// Overrides are checked in order; the first match wins.
+type Overrides []Override
+
+// Classify returns the severity of u: that of the first matching override,
+// or the one version.Classify derives from the versions.
+func (o Overrides) Classify(u arch.Update) version.Severity {
+	for _, r := range o {
+		if Match([]string{r.Pattern}, u.Name) {
+			return r.Severity
+		}
+	}
+	return version.Classify(u.Old, u.New)
+}
+
+// MinSeverity splits updates into those at least as severe as min and the
+// rest, so callers can hide the latter or count them separately.
+func MinSeverity(updates []arch.Update, min version.Severity, overrides Overrides) (kept, hidden []arch.Update) {
+	for _, u := range updates {
+		if overrides.Classify(u) >= min {
+			kept = append(kept, u)
+		} else {
+			hidden = append(hidden, u)
+		}
+	}
+	return kept, hidden
+}
+
+// ExcludeRepos drops the updates whose package comes from one of the
+// excluded repositories, looked up in repoOf as returned by
+// arch.PackageRepos.
+func ExcludeRepos(updates []arch.Update, repoOf map[string]string, excluded []string) []arch.Update {
+	if len(excluded) == 0 {
+		return updates
+	}
+	skip := make(map[string]bool, len(excluded))
+	for _, r := range excluded {
+		skip[r] = true
+	}
+	var kept []arch.Update
+	for _, u := range updates {
+		if !skip[repoOf[u.Name]] {
+			kept = append(kept, u)
+		}
+	}
+	return kept
+}
+
+// Only keeps the updates whose package name matches one of the shell
+// patterns, such as "linux*" or "mesa". No patterns keeps everything.
+func Only(updates []arch.Update, patterns []string) []arch.Update {
+	if len(patterns) == 0 {
+		return updates
+	}
+	var kept []arch.Update
+	for _, u := range updates {
+		if Match(patterns, u.Name) {
+			kept = append(kept, u)
+		}
+	}
+	return kept
+}
+
+// Match reports whether name matches any of the shell patterns. Malformed
+// patterns never match.
+func Match(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ByGroup collapses updates into the package groups they belong to, as
+// returned by arch.Groups. A group is only formed when at least min of its
+// packages have updates; every other update is returned in rest. Packages
+// in several groups go to the first one that qualifies.
+func ByGroup(updates []arch.Update, groupsOf map[string][]string, min int) (groups map[string][]arch.Update, rest []arch.Update) {
+	counts := make(map[string]int)
+	for _, u := range updates {
+		for _, g := range groupsOf[u.Name] {
+			counts[g]++
+		}
+	}
+	groups = make(map[string][]arch.Update)
+	for _, u := range updates {
+		grouped := false
+		for _, g := range groupsOf[u.Name] {
+			if counts[g] >= min {
+				groups[g] = append(groups[g], u)
+				grouped = true
+				break
+			}
+		}
+		if !grouped {
+			rest = append(rest, u)
+		}
+	}
+	return groups, rest
+}
+
+// Explicit splits updates into explicitly installed packages and
+// dependencies, according to the set returned by arch.Explicit.
+func Explicit(updates []arch.Update, explicit map[string]bool) (kept, deps []arch.Update) {
+	for _, u := range updates {
+		if explicit[u.Name] {
+			kept = append(kept, u)
+		} else {
+			deps = append(deps, u)
+		}
+	}
+	return kept, deps
+}
+
+// Watched returns the updates to packages on the watch list, shell patterns
+// as for Match. Unlike Only, an empty watch list matches nothing.
+func Watched(updates []arch.Update, watch []string) []arch.Update {
+	var hits []arch.Update
+	for _, u := range updates {
+		if Match(watch, u.Name) {
+			hits = append(hits, u)
+		}
+	}
+	return hits
+}
@@ -0,0 +1,32 @@
+package history
+
+import (
+	"fmt"
+	"time"
+)
+
+// Digest summarizes a week of updates for the weekly notification.
+type Digest struct {
+	// Applied is how many package upgrades were installed in the week.
+	Applied int
+	// Pending is how many updates are currently pending.
+	Pending int
+	// Oldest is how long the oldest pending update has been waiting.
+	Oldest time.Duration
+}
+
+func (d Digest) String() string {
+	s := fmt.Sprintf("This week: %s applied, %d currently pending", count(d.Applied, "update"), d.Pending)
+	if d.Pending > 0 {
+		s += ", oldest pending: " + count(int(d.Oldest/(24*time.Hour)), "day")
+	}
+	return s
+}
+
+// count formats n with noun, made plural unless n is 1.
+func count(n int, noun string) string {
+	if n != 1 {
+		noun += "s"
+	}
+	return fmt.Sprintf("%d %s", n, noun)
+}
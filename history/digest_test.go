@@ -0,0 +1,25 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDigestString(t *testing.T) {
+	const day = 24 * time.Hour
+	tests := []struct {
+		d    Digest
+		want string
+	}{
+		{Digest{}, "This week: 0 updates applied, 0 currently pending"},
+		{Digest{Applied: 1}, "This week: 1 update applied, 0 currently pending"},
+		{Digest{Applied: 12, Pending: 1, Oldest: day + time.Hour}, "This week: 12 updates applied, 1 currently pending, oldest pending: 1 day"},
+		{Digest{Applied: 3, Pending: 4, Oldest: 6 * day}, "This week: 3 updates applied, 4 currently pending, oldest pending: 6 days"},
+		{Digest{Pending: 2, Oldest: time.Hour}, "This week: 0 updates applied, 2 currently pending, oldest pending: 0 days"},
+	}
+	for _, tt := range tests {
+		if got := tt.d.String(); got != tt.want {
+			t.Errorf("%+v: got %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Package history keeps an append-only log of check results, one JSON
+// object per line.
+package history
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+// Record is the outcome of one check.
+type Record struct {
+	Time time.Time `json:"time"`
+	// Counts maps a source name to its number of pending updates.
+	Counts map[string]int `json:"counts"`
+	// Errors maps a source name to the error its check failed with.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// Append adds r as a line at the end of the log at path, creating it if
+// needed.
+func Append(path string, r Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+	if _, err := f.Write(append(b, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("writing history: %w", err)
+	}
+	return f.Close()
+}
+
+// Read parses the log at path, skipping malformed lines such as one left
+// truncated by a crash.
+func Read(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening history: %w", err)
+	}
+	defer f.Close()
+	var records []Record
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var r Record
+		if json.Unmarshal(sc.Bytes(), &r) == nil {
+			records = append(records, r)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+	return records, nil
+}
+
+// AveragePending returns the mean number of pending updates, all sources
+// together, over the records since the given time.
+func AveragePending(records []Record, since time.Time) float64 {
+	total, n := 0, 0
+	for _, r := range records {
+		if r.Time.Before(since) {
+			continue
+		}
+		for _, c := range r.Counts {
+			total += c
+		}
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return float64(total) / float64(n)
+}
+
+// Top returns the n names with the highest counts, such as the most
+// frequently upgraded packages from arch.Upgraded, highest first.
+func Top(counts map[string]int, n int) []string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+	return names[:min(max(n, 0), len(names))]
+}
@@ -0,0 +1,36 @@
+// Package notify deals with the desktop notification daemon.
+package notify
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// DoNotDisturb reports whether the notification daemon is currently
+// withholding notifications. It asks dunst and swaync through their control
+// tools, then any daemon exposing the standard Inhibited property.
+func DoNotDisturb(ctx context.Context, r runner.Runner) (bool, error) {
+	probes := [][]string{
+		{"dunstctl", "is-paused"},
+		{"swaync-client", "--get-dnd"},
+		{"busctl", "--user", "get-property", "org.freedesktop.Notifications",
+			"/org/freedesktop/Notifications", "org.freedesktop.Notifications", "Inhibited"},
+	}
+	for _, p := range probes {
+		if _, err := r.LookPath(p[0]); err != nil {
+			continue
+		}
+		out, err := runner.Output(ctx, r, p[0], p[1:]...)
+		if err != nil {
+			// The tool is installed but its daemon isn't running.
+			continue
+		}
+		// "true", or "b true" from busctl.
+		f := strings.Fields(string(out))
+		return len(f) > 0 && f[len(f)-1] == "true", nil
+	}
+	return false, errors.New("no notification daemon reports its do-not-disturb state")
+}
@@ -0,0 +1,70 @@
+// Package remote checks for updates on other hosts over SSH.
+package remote
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/lmcanavals/waybar-updates-btw/arch"
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// Result is the outcome of checking one host.
+type Result struct {
+	Host    string
+	Updates []arch.Update
+	Err     error
+}
+
+// Check runs checkupdates on host through ssh. BatchMode keeps ssh from
+// prompting for passwords, since nobody is there to answer.
+func Check(ctx context.Context, r runner.Runner, host string) ([]arch.Update, error) {
+	out, err := runner.Output(ctx, r, "ssh", "-o", "BatchMode=yes", host, "checkupdates")
+	if err != nil {
+		// checkupdates exits with 2 when there are no updates.
+		if runner.ExitCode(err) == 2 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("checking %s: %w", host, err)
+	}
+	var updates []arch.Update
+	for _, line := range strings.Split(string(out), "\n") {
+		if u, ok := arch.ParseUpdate(line); ok {
+			updates = append(updates, u)
+		}
+	}
+	return updates, nil
+}
+
+// CheckAll checks every host concurrently and returns the results in the
+// order of hosts.
+func CheckAll(ctx context.Context, r runner.Runner, hosts []string) []Result {
+	results := make([]Result, len(hosts))
+	var wg sync.WaitGroup
+	for i, h := range hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			updates, err := Check(ctx, r, h)
+			results[i] = Result{Host: h, Updates: updates, Err: err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Summary describes the per-host counts, like "server1: 12, nas: 3". Hosts
+// that failed are shown with a "?".
+func Summary(results []Result) string {
+	parts := make([]string, len(results))
+	for i, r := range results {
+		if r.Err != nil {
+			parts[i] = r.Host + ": ?"
+		} else {
+			parts[i] = fmt.Sprintf("%s: %d", r.Host, len(r.Updates))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
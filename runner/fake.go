@@ -0,0 +1,115 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Result is the canned outcome of a command.
+type Result struct {
+	Stdout string
+	// Stderr is carried by the *ExitError of a failed command.
+	Stderr string
+	// Code is the exit status; non-zero makes Output return an *ExitError.
+	Code int
+}
+
+// Fake is a Runner that answers with canned results instead of running
+// anything, and records the commands it was asked to run.
+type Fake struct {
+	// Results maps command lines, as Command.String returns them, to their
+	// outcome. Unknown commands fail.
+	Results map[string]Result
+	// Paths maps the executables LookPath finds to their path.
+	Paths map[string]string
+
+	mu    sync.Mutex
+	calls []Command
+}
+
+func (f *Fake) Output(ctx context.Context, cmd Command) ([]byte, error) {
+	f.mu.Lock()
+	f.calls = append(f.calls, cmd)
+	f.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	res, ok := f.Results[cmd.String()]
+	if !ok {
+		return nil, fmt.Errorf("fake: unexpected command %q", cmd)
+	}
+	if res.Code != 0 {
+		return []byte(res.Stdout), &ExitError{Code: res.Code, Stderr: []byte(res.Stderr)}
+	}
+	return []byte(res.Stdout), nil
+}
+
+func (f *Fake) LookPath(file string) (string, error) {
+	if p, ok := f.Paths[file]; ok {
+		return p, nil
+	}
+	return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+}
+
+// Calls returns the commands run so far, in order.
+func (f *Fake) Calls() []Command {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Command(nil), f.calls...)
+}
+
+// Response is a canned HTTP response.
+type Response struct {
+	Status int
+	Body   string
+}
+
+// FakeDoer is a Doer that answers with canned responses, looked up by the
+// full request URL and then by the URL without its query. Unknown URLs get
+// a 404.
+type FakeDoer struct {
+	Responses map[string]Response
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (f *FakeDoer) Do(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.mu.Unlock()
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	res, ok := f.Responses[req.URL.String()]
+	if !ok {
+		u := *req.URL
+		u.RawQuery = ""
+		res, ok = f.Responses[u.String()]
+	}
+	if !ok {
+		res = Response{Status: http.StatusNotFound}
+	}
+	if res.Status == 0 {
+		res.Status = http.StatusOK
+	}
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", res.Status, http.StatusText(res.Status)),
+		StatusCode: res.Status,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(res.Body)),
+		Request:    req,
+	}, nil
+}
+
+// Requests returns the requests received so far, in order.
+func (f *FakeDoer) Requests() []*http.Request {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*http.Request(nil), f.requests...)
+}
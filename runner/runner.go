@@ -0,0 +1,86 @@
+// Package runner abstracts running commands and making HTTP requests, so
+// the code inspecting the system can be exercised against canned results.
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Command is a program to run.
+type Command struct {
+	Name string
+	Args []string
+	// Env holds KEY=value pairs added to the inherited environment.
+	Env []string
+}
+
+// String returns the command line, which is also how Fake looks commands
+// up.
+func (c Command) String() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
+}
+
+// Runner runs commands.
+type Runner interface {
+	// Output runs cmd and returns its standard output. A non-zero exit
+	// status is reported as an *ExitError, together with the output.
+	Output(ctx context.Context, cmd Command) ([]byte, error)
+	// LookPath searches for an executable like exec.LookPath.
+	LookPath(file string) (string, error)
+}
+
+// Output runs name with args on r.
+func Output(ctx context.Context, r Runner, name string, args ...string) ([]byte, error) {
+	return r.Output(ctx, Command{Name: name, Args: args})
+}
+
+// ExitError reports a command that exited with a non-zero status.
+type ExitError struct {
+	Code   int
+	Stderr []byte
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// ExitCode returns the exit status carried by err, or -1 when err is not an
+// *ExitError.
+func ExitCode(err error) int {
+	var e *ExitError
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return -1
+}
+
+// Exec runs commands for real.
+type Exec struct{}
+
+func (Exec) Output(ctx context.Context, cmd Command) ([]byte, error) {
+	c := exec.CommandContext(ctx, cmd.Name, cmd.Args...)
+	if len(cmd.Env) > 0 {
+		c.Env = append(os.Environ(), cmd.Env...)
+	}
+	out, err := c.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+		return out, &ExitError{Code: exitErr.ExitCode(), Stderr: exitErr.Stderr}
+	}
+	return out, err
+}
+
+func (Exec) LookPath(file string) (string, error) {
+	return exec.LookPath(file)
+}
+
+// Doer sends HTTP requests; *http.Client is one.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
@@ -0,0 +1,53 @@
+package schedule
+
+import (
+	"context"
+	"time"
+)
+
+// Debounce coalesces bursts of values from in: a value is passed on only
+// once wait has elapsed without a newer one arriving, and then only the
+// latest. The returned channel is closed when in is closed, after flushing
+// any pending value, or when ctx is done.
+func Debounce[T any](ctx context.Context, in <-chan T, wait time.Duration) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		var (
+			latest  T
+			pending bool
+			timer   = time.NewTimer(wait)
+		)
+		timer.Stop()
+		defer timer.Stop()
+		for {
+			select {
+			case v, ok := <-in:
+				if !ok {
+					if pending {
+						select {
+						case out <- latest:
+						case <-ctx.Done():
+						}
+					}
+					return
+				}
+				latest, pending = v, true
+				timer.Reset(wait)
+			case <-timer.C:
+				if !pending {
+					continue
+				}
+				select {
+				case out <- latest:
+					pending = false
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
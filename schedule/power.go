@@ -0,0 +1,57 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// Power is the power supply state reported by UPower.
+type Power struct {
+	OnBattery bool
+	// Percentage is the battery charge, 0 to 100.
+	Percentage float64
+}
+
+// PowerSave reports whether checks should be throttled: the system runs on
+// battery and, when threshold is positive, the charge is below it.
+func (p Power) PowerSave(threshold float64) bool {
+	return p.OnBattery && (threshold <= 0 || p.Percentage < threshold)
+}
+
+// ReadPower queries UPower over the system bus.
+func ReadPower(ctx context.Context, r runner.Runner) (Power, error) {
+	var p Power
+	v, err := upowerProperty(ctx, r, "/org/freedesktop/UPower", "org.freedesktop.UPower", "OnBattery")
+	if err != nil {
+		return p, err
+	}
+	p.OnBattery = v == "true"
+
+	v, err = upowerProperty(ctx, r, "/org/freedesktop/UPower/devices/DisplayDevice", "org.freedesktop.UPower.Device", "Percentage")
+	if err != nil {
+		return p, err
+	}
+	if p.Percentage, err = strconv.ParseFloat(v, 64); err != nil {
+		return p, fmt.Errorf("parsing battery percentage: %w", err)
+	}
+	return p, nil
+}
+
+// upowerProperty reads a property with busctl, which prints it as
+// "<signature> <value>".
+func upowerProperty(ctx context.Context, r runner.Runner, path, iface, prop string) (string, error) {
+	out, err := runner.Output(ctx, r, "busctl", "--system", "get-property",
+		"org.freedesktop.UPower", path, iface, prop)
+	if err != nil {
+		return "", fmt.Errorf("reading UPower %s: %w", prop, err)
+	}
+	_, v, ok := strings.Cut(strings.TrimSpace(string(out)), " ")
+	if !ok {
+		return "", fmt.Errorf("unexpected UPower %s: %q", prop, out)
+	}
+	return v, nil
+}
@@ -0,0 +1,53 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a daily time range such as quiet hours. It may wrap around
+// midnight, as in 23:00-07:00. A Window whose Start equals End is empty.
+type Window struct {
+	// Start and End are offsets from midnight.
+	Start, End time.Duration
+}
+
+// ParseWindow parses a "HH:MM-HH:MM" range. A range that starts where it
+// ends, such as 08:00-08:00, is rejected as ambiguous.
+func ParseWindow(s string) (Window, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return Window{}, fmt.Errorf("invalid time range %q: want HH:MM-HH:MM", s)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return Window{}, err
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return Window{}, err
+	}
+	if start == end {
+		return Window{}, fmt.Errorf("invalid time range %q: start and end are equal", s)
+	}
+	return Window{Start: start, End: end}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: want HH:MM", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains reports whether t, in its own location, falls inside the window.
+func (w Window) Contains(t time.Time) bool {
+	h, m, s := t.Clock()
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+	if w.Start <= w.End {
+		return d >= w.Start && d < w.End
+	}
+	return d >= w.Start || d < w.End
+}
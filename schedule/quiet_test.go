@@ -0,0 +1,55 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Window
+		wantErr bool
+	}{
+		{"09:00-17:30", Window{Start: 9 * time.Hour, End: 17*time.Hour + 30*time.Minute}, false},
+		{"23:00 - 07:00", Window{Start: 23 * time.Hour, End: 7 * time.Hour}, false},
+		{"08:00-08:00", Window{}, true},
+		{"08:00", Window{}, true},
+		{"25:00-07:00", Window{}, true},
+		{"23:00-7am", Window{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseWindow(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseWindow(%q) = %v, %v, want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestWindowContains(t *testing.T) {
+	day := Window{Start: 9 * time.Hour, End: 17 * time.Hour}
+	night := Window{Start: 23 * time.Hour, End: 7 * time.Hour}
+	at := func(h, m int) time.Time { return time.Date(2024, 6, 1, h, m, 0, 0, time.UTC) }
+	tests := []struct {
+		w    Window
+		t    time.Time
+		want bool
+	}{
+		{day, at(8, 59), false},
+		{day, at(9, 0), true},
+		{day, at(12, 0), true},
+		{day, at(17, 0), false},
+		{night, at(22, 59), false},
+		{night, at(23, 0), true},
+		{night, at(0, 0), true},
+		{night, at(6, 59), true},
+		{night, at(7, 0), false},
+		{night, at(12, 0), false},
+		{Window{Start: 8 * time.Hour, End: 8 * time.Hour}, at(8, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.w.Contains(tt.t); got != tt.want {
+			t.Errorf("%v.Contains(%s) = %v, want %v", tt.w, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Package schedule decides when checks run.
+package schedule
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// Jitter returns d plus a random duration in [0, max), so machines started
+// at the same moment don't all reach the mirrors and aurweb together.
+func Jitter(d, max time.Duration) time.Duration {
+	if max <= 0 {
+		return d
+	}
+	return d + rand.N(max)
+}
+
+// Sleep waits for d or until ctx is done, returning ctx.Err() in the latter
+// case.
+func Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
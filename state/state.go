@@ -0,0 +1,209 @@
+// Package state persists the last known check results across restarts.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lmcanavals/waybar-updates-btw/arch"
+)
+
+// State is what is remembered between runs.
+type State struct {
+	// Sources maps a backend name, such as "pacman" or "aur", to its last
+	// result.
+	Sources map[string]Source `json:"sources"`
+	// Acked maps acknowledged or snoozed packages to the version they were
+	// acknowledged at.
+	Acked map[string]Ack `json:"acked,omitempty"`
+	// FirstSeen records when each pending update, keyed by package name
+	// and new version, was first seen.
+	FirstSeen map[string]time.Time `json:"first_seen,omitempty"`
+}
+
+// Ack hides an update from the badge while its new version is Version
+// and, for snoozes, until Until.
+type Ack struct {
+	Version string `json:"version"`
+	// Until is when a snooze expires, zero for a plain acknowledgment.
+	Until time.Time `json:"until,omitzero"`
+}
+
+// Source is the last result of a backend.
+type Source struct {
+	Checked time.Time     `json:"checked"`
+	Updates []arch.Update `json:"updates"`
+	// Error is why the last check failed, empty when it succeeded.
+	Error string `json:"error,omitempty"`
+}
+
+// Path returns $XDG_CACHE_HOME/waybar-updates-btw/state.json.
+func Path() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "waybar-updates-btw", "state.json"), nil
+}
+
+// Load reads the state at path. A missing file is an empty state.
+func Load(path string) (*State, error) {
+	s := &State{Sources: make(map[string]Source)}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading state: %w", err)
+	}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("decoding state: %w", err)
+	}
+	if s.Sources == nil {
+		s.Sources = make(map[string]Source)
+	}
+	return s, nil
+}
+
+// Save writes the state to path, replacing it atomically so a crash never
+// leaves a truncated file behind.
+func (s *State) Save(path string) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".state-*.json")
+	if err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("saving state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	return nil
+}
+
+// Ack marks updates as seen, so they stay out of the badge until newer
+// versions appear.
+func (s *State) Ack(updates []arch.Update) {
+	s.Snooze(updates, time.Time{})
+}
+
+// Snooze hides updates from the badge until t, or until newer versions
+// appear if that happens first. Updates that show up later are not
+// affected.
+func (s *State) Snooze(updates []arch.Update, t time.Time) {
+	if s.Acked == nil {
+		s.Acked = make(map[string]Ack)
+	}
+	for _, u := range updates {
+		s.Acked[u.Name] = Ack{Version: u.New, Until: t}
+	}
+}
+
+// Acknowledged reports whether u should be left out of the badge at now,
+// being acknowledged or snoozed at its current new version. Such updates
+// are still meant to be listed, dimmed, in the tooltip.
+func (s *State) Acknowledged(u arch.Update, now time.Time) bool {
+	a, ok := s.Acked[u.Name]
+	return ok && a.Version == u.New && (a.Until.IsZero() || now.Before(a.Until))
+}
+
+func seenKey(u arch.Update) string {
+	return u.Name + " " + u.New
+}
+
+// Observe records now as the first-seen time of the updates in Sources not
+// seen before, and forgets the updates no longer pending in any source
+// along with their acknowledgments and the snoozes expired at now. Call it
+// after storing a source's new result.
+func (s *State) Observe(now time.Time) {
+	seen := make(map[string]time.Time, len(s.FirstSeen))
+	var acked map[string]Ack
+	for _, src := range s.Sources {
+		for _, u := range src.Updates {
+			k := seenKey(u)
+			if t, ok := s.FirstSeen[k]; ok {
+				seen[k] = t
+			} else {
+				seen[k] = now
+			}
+			if s.Acknowledged(u, now) {
+				if acked == nil {
+					acked = make(map[string]Ack)
+				}
+				acked[u.Name] = s.Acked[u.Name]
+			}
+		}
+	}
+	s.FirstSeen = seen
+	s.Acked = acked
+}
+
+// PendingFor returns how long u has been pending as of now, zero when it
+// was never observed.
+func (s *State) PendingFor(u arch.Update, now time.Time) time.Duration {
+	t, ok := s.FirstSeen[seenKey(u)]
+	if !ok {
+		return 0
+	}
+	return now.Sub(t)
+}
+
+// Stale returns the updates that have been pending longer than maxAge as of
+// now.
+func (s *State) Stale(updates []arch.Update, now time.Time, maxAge time.Duration) []arch.Update {
+	var stale []arch.Update
+	for _, u := range updates {
+		if s.PendingFor(u, now) > maxAge {
+			stale = append(stale, u)
+		}
+	}
+	return stale
+}
+
+// Status describes the health of the named sources as of now, like
+// "pacman ✓ 2m · aur ✗ timeout". Sources never checked are shown with a "?".
+func (s *State) Status(names []string, now time.Time) string {
+	parts := make([]string, len(names))
+	for i, name := range names {
+		src, ok := s.Sources[name]
+		switch {
+		case !ok:
+			parts[i] = name + " ?"
+		case src.Error != "":
+			parts[i] = name + " ✗ " + src.Error
+		default:
+			parts[i] = name + " ✓ " + ago(now.Sub(src.Checked))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// ago formats d in its largest whole unit: "now", "5m", "3h" or "2d".
+func ago(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+}
@@ -0,0 +1,102 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lmcanavals/waybar-updates-btw/arch"
+)
+
+var now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestSnooze(t *testing.T) {
+	vim := arch.Update{Name: "vim", Old: "9.1.0-1", New: "9.1.1-1"}
+	newer := arch.Update{Name: "vim", Old: "9.1.0-1", New: "9.1.2-1"}
+	s := &State{}
+	s.Snooze([]arch.Update{vim}, now.Add(time.Hour))
+
+	tests := []struct {
+		name string
+		u    arch.Update
+		at   time.Time
+		want bool
+	}{
+		{"before until", vim, now.Add(30 * time.Minute), true},
+		{"at until", vim, now.Add(time.Hour), false},
+		{"after until", vim, now.Add(2 * time.Hour), false},
+		{"newer version", newer, now.Add(30 * time.Minute), false},
+		{"other package", arch.Update{Name: "git", New: "2.45.1-1"}, now, false},
+	}
+	for _, tt := range tests {
+		if got := s.Acknowledged(tt.u, tt.at); got != tt.want {
+			t.Errorf("%s: Acknowledged = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAck(t *testing.T) {
+	vim := arch.Update{Name: "vim", Old: "9.1.0-1", New: "9.1.1-1"}
+	s := &State{}
+	s.Ack([]arch.Update{vim})
+	if !s.Acknowledged(vim, now.AddDate(1, 0, 0)) {
+		t.Error("acknowledgment expired")
+	}
+	vim.New = "9.1.2-1"
+	if s.Acknowledged(vim, now) {
+		t.Error("newer version hidden by an acknowledgment of the older one")
+	}
+}
+
+func TestObservePrunesAcks(t *testing.T) {
+	vim := arch.Update{Name: "vim", Old: "9.1.0-1", New: "9.1.1-1"}
+	git := arch.Update{Name: "git", Old: "2.45.0-1", New: "2.45.1-1"}
+	curl := arch.Update{Name: "curl", Old: "8.7.1-1", New: "8.8.0-1"}
+	s := &State{Sources: map[string]Source{
+		"pacman": {Updates: []arch.Update{vim, git, curl}},
+	}}
+	s.Ack([]arch.Update{vim, git})
+	s.Snooze([]arch.Update{curl}, now)
+
+	// git was upgraded, vim has a newer version and curl's snooze expired.
+	vim.New = "9.1.2-1"
+	s.Sources["pacman"] = Source{Updates: []arch.Update{vim, curl}}
+	s.Observe(now)
+	if len(s.Acked) != 0 {
+		t.Errorf("Acked = %v, want the stale entries pruned", s.Acked)
+	}
+
+	s.Ack([]arch.Update{vim})
+	s.Observe(now)
+	if _, ok := s.Acked["vim"]; !ok {
+		t.Error("pruned the acknowledgment of a pending update")
+	}
+}
+
+func TestObserveKeepsOtherSources(t *testing.T) {
+	vim := arch.Update{Name: "vim", Old: "9.1.0-1", New: "9.1.1-1"}
+	yay := arch.Update{Name: "yay", Old: "12.3.4-1", New: "12.3.5-1"}
+	paru := arch.Update{Name: "paru", Old: "2.0.2-1", New: "2.0.3-1"}
+	s := &State{Sources: map[string]Source{
+		"pacman": {Updates: []arch.Update{vim}},
+		"aur":    {Updates: []arch.Update{yay}},
+	}}
+	s.Observe(now)
+
+	// Only the AUR check runs again, an hour later.
+	later := now.Add(time.Hour)
+	s.Sources["aur"] = Source{Updates: []arch.Update{yay, paru}}
+	s.Observe(later)
+
+	for _, tt := range []struct {
+		u    arch.Update
+		want time.Duration
+	}{
+		{vim, time.Hour},
+		{yay, time.Hour},
+		{paru, 0},
+	} {
+		if got := s.PendingFor(tt.u, later); got != tt.want {
+			t.Errorf("PendingFor(%s) = %v, want %v", tt.u.Name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Gradient is a list of "#rrggbb" color stops, from few updates to many.
+type Gradient []string
+
+// At returns the color for count updates, interpolating along the stops so
+// that zero maps to the first stop and limit or more to the last. Invalid
+// stops are reported as errors.
+func (g Gradient) At(count, limit int) (string, error) {
+	if len(g) == 0 {
+		return "", errors.New("empty gradient")
+	}
+	rgb := make([][3]float64, len(g))
+	for i, s := range g {
+		c, ok := hexColor(s)
+		if !ok {
+			return "", fmt.Errorf("invalid gradient color %q", s)
+		}
+		for j := range 3 {
+			v, _ := strconv.ParseUint(c[1+2*j:3+2*j], 16, 8)
+			rgb[i][j] = float64(v)
+		}
+	}
+	if len(rgb) == 1 || limit <= 0 {
+		return g.hex(rgb[len(rgb)-1]), nil
+	}
+
+	t := max(min(float64(count)/float64(limit), 1), 0)
+	// Position along the stops: segment i blends stop i into stop i+1.
+	pos := t * float64(len(rgb)-1)
+	i := min(int(pos), len(rgb)-2)
+	f := pos - float64(i)
+	var c [3]float64
+	for j := range 3 {
+		c[j] = rgb[i][j] + (rgb[i+1][j]-rgb[i][j])*f
+	}
+	return g.hex(c), nil
+}
+
+func (Gradient) hex(c [3]float64) string {
+	return fmt.Sprintf("#%02x%02x%02x", int(c[0]+0.5), int(c[1]+0.5), int(c[2]+0.5))
+}
@@ -0,0 +1,56 @@
+package theme
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/runner"
+)
+
+// Scheme is the desktop color-scheme preference, as defined by the
+// freedesktop appearance settings.
+type Scheme uint32
+
+const (
+	NoPreference Scheme = iota
+	Dark
+	Light
+)
+
+// Palettes pairs the palettes to use under dark and light desktop themes.
+type Palettes struct {
+	Dark  Palette
+	Light Palette
+}
+
+// For returns the palette matching s. Without a preference the dark one is
+// used, as bars are commonly dark.
+func (ps Palettes) For(s Scheme) Palette {
+	if s == Light {
+		return ps.Light
+	}
+	return ps.Dark
+}
+
+// ColorScheme asks the settings portal for the current color-scheme.
+func ColorScheme(ctx context.Context, r runner.Runner) (Scheme, error) {
+	out, err := runner.Output(ctx, r, "busctl", "--user", "call",
+		"org.freedesktop.portal.Desktop", "/org/freedesktop/portal/desktop",
+		"org.freedesktop.portal.Settings", "ReadOne", "ss",
+		"org.freedesktop.appearance", "color-scheme")
+	if err != nil {
+		return NoPreference, fmt.Errorf("reading color-scheme: %w", err)
+	}
+	// The reply is a variant holding a uint32: "v u 1".
+	f := strings.Fields(string(out))
+	if len(f) == 0 {
+		return NoPreference, fmt.Errorf("unexpected color-scheme reply %q", out)
+	}
+	n, err := strconv.ParseUint(f[len(f)-1], 10, 32)
+	if err != nil {
+		return NoPreference, fmt.Errorf("unexpected color-scheme reply %q", out)
+	}
+	return Scheme(n), nil
+}
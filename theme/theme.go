@@ -0,0 +1,145 @@
+// Package theme holds the colors used to tell update severities apart.
+package theme
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lmcanavals/waybar-updates-btw/version"
+)
+
+// Palette assigns a color, as "#rrggbb", to each severity.
+type Palette struct {
+	Major string
+	Minor string
+	Patch string
+	Other string
+}
+
+// Default is the Tokyo Night palette.
+var Default = Palette{
+	Major: "#f7768e",
+	Minor: "#e0af68",
+	Patch: "#9ece6a",
+	Other: "#7aa2f7",
+}
+
+// Color returns the color for severity s.
+func (p Palette) Color(s version.Severity) string {
+	switch s {
+	case version.Major:
+		return p.Major
+	case version.Minor:
+		return p.Minor
+	case version.Patch:
+		return p.Patch
+	}
+	return p.Other
+}
+
+// Load reads a palette from a generated colorscheme: a pywal colors.json, a
+// base16 YAML scheme (.yaml or .yml) or, for any other file, Xresources.
+// Red marks major updates, yellow minor, green patch and blue the rest.
+// Colors missing from the file keep their Default value.
+func Load(path string) (Palette, error) {
+	var (
+		colors map[string]string
+		err    error
+		keys   [4]string // major, minor, patch, other
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		colors, err = readPywal(path)
+		keys = [4]string{"color1", "color3", "color2", "color4"}
+	case ".yaml", ".yml":
+		colors, err = readKeyValues(path, ":")
+		keys = [4]string{"base08", "base0A", "base0B", "base0D"}
+	default:
+		colors, err = readXresources(path)
+		keys = [4]string{"color1", "color3", "color2", "color4"}
+	}
+	if err != nil {
+		return Default, fmt.Errorf("reading theme %s: %w", path, err)
+	}
+
+	p := Default
+	for i, dst := range []*string{&p.Major, &p.Minor, &p.Patch, &p.Other} {
+		if c, ok := hexColor(colors[keys[i]]); ok {
+			*dst = c
+		}
+	}
+	return p, nil
+}
+
+// hexColor normalizes "#abcdef", "abcdef" or a quoted form of either.
+func hexColor(s string) (string, bool) {
+	s = strings.Trim(strings.TrimSpace(s), `"'`)
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return "", false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return "", false
+		}
+	}
+	return "#" + strings.ToLower(s), true
+}
+
+func readPywal(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var wal struct {
+		Colors map[string]string `json:"colors"`
+	}
+	if err := json.Unmarshal(b, &wal); err != nil {
+		return nil, err
+	}
+	return wal.Colors, nil
+}
+
+// readKeyValues reads "key<sep> value" lines, ignoring indentation and
+// comments, which covers base16 schemes without needing a YAML parser.
+func readKeyValues(path, sep string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	kv := make(map[string]string)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, sep); ok {
+			v, _, _ = strings.Cut(strings.TrimSpace(v), " #")
+			kv[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return kv, sc.Err()
+}
+
+// readXresources reads "*.color1: #rrggbb" style resources, keyed by the
+// resource name after the last separator.
+func readXresources(path string) (map[string]string, error) {
+	kv, err := readKeyValues(path, ":")
+	if err != nil {
+		return nil, err
+	}
+	colors := make(map[string]string, len(kv))
+	for k, v := range kv {
+		if i := strings.LastIndexAny(k, ".*"); i >= 0 {
+			k = k[i+1:]
+		}
+		colors[k] = v
+	}
+	return colors, nil
+}
@@ -0,0 +1,124 @@
+package version
+
+import "strings"
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+func isAlpha(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isAlnum(c byte) bool { return isDigit(c) || isAlpha(c) }
+
+// parseEVR splits "epoch:version-release". The epoch defaults to "0" and
+// the release is empty when missing.
+func parseEVR(evr string) (epoch, ver, rel string) {
+	i := 0
+	for i < len(evr) && isDigit(evr[i]) {
+		i++
+	}
+	epoch, ver = "0", evr
+	if i < len(evr) && evr[i] == ':' {
+		if i > 0 {
+			epoch = evr[:i]
+		}
+		ver = evr[i+1:]
+	}
+	if j := strings.LastIndexByte(ver, '-'); j >= 0 {
+		ver, rel = ver[:j], ver[j+1:]
+	}
+	return epoch, ver, rel
+}
+
+// Compare orders two package versions the way pacman's vercmp does,
+// returning -1, 0 or 1 when a is older than, equal to or newer than b.
+func Compare(a, b string) int {
+	if a == b {
+		return 0
+	}
+	ea, va, ra := parseEVR(a)
+	eb, vb, rb := parseEVR(b)
+	if c := rpmvercmp(ea, eb); c != 0 {
+		return c
+	}
+	if c := rpmvercmp(va, vb); c != 0 {
+		return c
+	}
+	if ra != "" && rb != "" {
+		return rpmvercmp(ra, rb)
+	}
+	return 0
+}
+
+// rpmvercmp compares version strings segment by segment, a port of libalpm's
+// algorithm.
+func rpmvercmp(a, b string) int {
+	if a == b {
+		return 0
+	}
+	i, j := 0, 0 // start of the current segments
+	for i < len(a) && j < len(b) {
+		si, sj := i, j
+		for i < len(a) && !isAlnum(a[i]) {
+			i++
+		}
+		for j < len(b) && !isAlnum(b[j]) {
+			j++
+		}
+		if i >= len(a) || j >= len(b) {
+			break
+		}
+		// More separators make a version newer: 1.0.1 > 1.0a.
+		if i-si != j-sj {
+			if i-si < j-sj {
+				return -1
+			}
+			return 1
+		}
+
+		ei, ej := i, j
+		isNum := isDigit(a[i])
+		if isNum {
+			for ei < len(a) && isDigit(a[ei]) {
+				ei++
+			}
+			for ej < len(b) && isDigit(b[ej]) {
+				ej++
+			}
+		} else {
+			for ei < len(a) && isAlpha(a[ei]) {
+				ei++
+			}
+			for ej < len(b) && isAlpha(b[ej]) {
+				ej++
+			}
+		}
+		// Segments of different kinds: numbers are newer than letters.
+		if ej == j {
+			if isNum {
+				return 1
+			}
+			return -1
+		}
+
+		sa, sb := a[i:ei], b[j:ej]
+		if isNum {
+			sa, sb = strings.TrimLeft(sa, "0"), strings.TrimLeft(sb, "0")
+			if len(sa) != len(sb) {
+				if len(sa) > len(sb) {
+					return 1
+				}
+				return -1
+			}
+		}
+		if c := strings.Compare(sa, sb); c != 0 {
+			return c
+		}
+		i, j = ei, ej
+	}
+
+	switch {
+	case i >= len(a) && j >= len(b):
+		return 0
+	// A remaining alpha segment never beats an empty one: 1.0 > 1.0rc.
+	case i >= len(a) && !(j < len(b) && isAlpha(b[j])), i < len(a) && isAlpha(a[i]):
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,95 @@
+package version
+
+import "testing"
+
+// Cases from pacman's test/util/vercmptest.sh.
+var compareTests = []struct {
+	a, b string
+	want int
+}{
+	// all similar length, no pkgrel
+	{"1.5.0", "1.5.0", 0},
+	{"1.5.1", "1.5.0", 1},
+
+	// mixed length
+	{"1.5.1", "1.5", 1},
+
+	// with pkgrel, simple
+	{"1.5.0-1", "1.5.0-1", 0},
+	{"1.5.0-1", "1.5.0-2", -1},
+	{"1.5.0-1", "1.5.1-1", -1},
+	{"1.5.0-2", "1.5.1-1", -1},
+
+	// with pkgrel, mixed lengths
+	{"1.5-1", "1.5.1-1", -1},
+	{"1.5-2", "1.5.1-1", -1},
+	{"1.5-2", "1.5.1-2", -1},
+
+	// mixed pkgrel inclusion
+	{"1.5", "1.5-1", 0},
+	{"1.5-1", "1.5", 0},
+	{"1.1-1", "1.1", 0},
+	{"1.0-1", "1.1", -1},
+	{"1.1-1", "1.0", 1},
+
+	// alphanumeric versions
+	{"1.5b-1", "1.5-1", -1},
+	{"1.5b", "1.5", -1},
+	{"1.5b-1", "1.5", -1},
+	{"1.5b", "1.5.1", -1},
+
+	// from the manpage
+	{"1.0a", "1.0alpha", -1},
+	{"1.0alpha", "1.0b", -1},
+	{"1.0b", "1.0beta", -1},
+	{"1.0beta", "1.0rc", -1},
+	{"1.0rc", "1.0", -1},
+
+	// going crazy? alpha-dotted versions
+	{"1.5.a", "1.5", 1},
+	{"1.5.b", "1.5.a", 1},
+	{"1.5.1", "1.5.b", 1},
+
+	// alpha dots and dashes
+	{"1.5.b-1", "1.5.b", 0},
+	{"1.5-1", "1.5.b", -1},
+
+	// same/similar content, differing separators
+	{"2.0", "2_0", 0},
+	{"2.0_a", "2_0.a", 0},
+	{"2.0a", "2.0.a", -1},
+	{"2___a", "2_a", 1},
+
+	// epoch included in version comparison
+	{"0:1.0", "0:1.0", 0},
+	{"0:1.0", "0:1.1", -1},
+	{"1:1.0", "0:1.0", 1},
+	{"1:1.0", "0:1.1", 1},
+	{"1:1.0", "2:1.1", -1},
+
+	// epoch + sometimes present
+	{"1:1.0", "1.0", 1},
+	{"0:1.0", "1.0", 0},
+	{"0:1.0", "1.1", -1},
+	{"0:1.1", "1.0", 1},
+	{"1:1.1", "1.1", 1},
+
+	// pkgrel dotted versions
+	{"1.0-1", "1.0-1.1", -1},
+	{"1.0-1.1", "1.0-1.2", -1},
+
+	// numeric segments beat alpha ones
+	{"1.0.1", "1.0a", 1},
+	{"1.1", "1.a", 1},
+}
+
+func TestCompare(t *testing.T) {
+	for _, tt := range compareTests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := Compare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks how large a version change is.
+type Severity int
+
+const (
+	// Other is a change past the third segment or a pkgrel-only rebuild.
+	Other Severity = iota
+	Patch
+	Minor
+	Major
+)
+
+var severityNames = [...]string{"other", "patch", "minor", "major"}
+
+func (s Severity) String() string {
+	if s < Other || s > Major {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+	return severityNames[s]
+}
+
+// split separates "epoch:pkgver-pkgrel" into epoch and pkgver, dropping the
+// pkgrel.
+func split(v string) (epoch, pkgver string) {
+	if e, rest, ok := strings.Cut(v, ":"); ok {
+		epoch, v = e, rest
+	}
+	if i := strings.LastIndexByte(v, '-'); i >= 0 {
+		v = v[:i]
+	}
+	return epoch, v
+}
+
+// Classify returns the severity of upgrading from older to newer: an epoch
+// bump or a change in the first pkgver segment is Major, the second Minor,
+// the third Patch, and anything else Other.
+func Classify(older, newer string) Severity {
+	oe, ov := split(older)
+	ne, nv := split(newer)
+	if oe != ne {
+		return Major
+	}
+	os, ns := segments(ov), segments(nv)
+	for i := 0; i < max(len(os), len(ns)); i++ {
+		if i < len(os) && i < len(ns) && os[i] == ns[i] {
+			continue
+		}
+		if i > int(Major-Patch) {
+			return Other
+		}
+		return Major - Severity(i)
+	}
+	return Other
+}
+
+// Summary describes how many updates there are of each severity, like
+// "34 updates: 3 major · 12 minor · 15 patch · 4 other". Severities with no
+// updates are left out.
+func Summary(sevs []Severity) string {
+	var counts [Major + 1]int
+	for _, s := range sevs {
+		counts[s]++
+	}
+	noun := "updates"
+	if len(sevs) == 1 {
+		noun = "update"
+	}
+	var parts []string
+	for s := Major; s >= Other; s-- {
+		if counts[s] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[s], s))
+		}
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("%d %s", len(sevs), noun)
+	}
+	return fmt.Sprintf("%d %s: %s", len(sevs), noun, strings.Join(parts, " · "))
+}
+
+// ParseSeverity parses a severity name as returned by Severity.String.
+func ParseSeverity(s string) (Severity, error) {
+	for i, name := range severityNames {
+		if strings.EqualFold(s, name) {
+			return Severity(i), nil
+		}
+	}
+	return Other, fmt.Errorf("unknown severity %q: want one of %s", s, strings.Join(severityNames[:], ", "))
+}
@@ -0,0 +1,54 @@
+package version
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		older, newer string
+		want         Severity
+	}{
+		{"1.2.3-1", "2.0.0-1", Major},
+		{"1.2.3-1", "1.3.0-1", Minor},
+		{"1.2.3-1", "1.2.4-1", Patch},
+		{"1.2.3.4-1", "1.2.3.5-1", Other},
+		{"1.2-1", "1.2-2", Other},
+		{"1:1.0-1", "2:1.0-1", Major},
+		{"1.0-1", "1:1.0-1", Major},
+		{"1.2-1", "1.2.1-1", Patch},
+		{"1.2.3-1", "1.3-1", Minor},
+		{"6.9.1.arch1-1", "6.9.2.arch1-1", Patch},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.older, tt.newer); got != tt.want {
+			t.Errorf("Classify(%q, %q) = %v, want %v", tt.older, tt.newer, got, tt.want)
+		}
+	}
+}
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		sevs []Severity
+		want string
+	}{
+		{nil, "0 updates"},
+		{[]Severity{Patch}, "1 update: 1 patch"},
+		{[]Severity{Other, Major, Patch, Major}, "4 updates: 2 major · 1 patch · 1 other"},
+	}
+	for _, tt := range tests {
+		if got := Summary(tt.sevs); got != tt.want {
+			t.Errorf("Summary(%v) = %q, want %q", tt.sevs, got, tt.want)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for s := Other; s <= Major; s++ {
+		got, err := ParseSeverity(s.String())
+		if err != nil || got != s {
+			t.Errorf("ParseSeverity(%q) = %v, %v, want %v", s.String(), got, err, s)
+		}
+	}
+	if _, err := ParseSeverity("huge"); err == nil {
+		t.Error("ParseSeverity(\"huge\") succeeded, want an error")
+	}
+}
@@ -0,0 +1,110 @@
+// Package version compares pacman package versions segment by segment.
+package version
+
+import (
+	"strings"
+	"unicode"
+)
+
+// token is a run of digits, of letters, or of separators in a version
+// string.
+type token struct {
+	text string
+	sep  bool
+}
+
+func class(r rune) int {
+	switch {
+	case unicode.IsDigit(r):
+		return 0
+	case unicode.IsLetter(r):
+		return 1
+	}
+	return 2
+}
+
+// tokenize splits v into runs of the same character class, so "1.2rc1-3"
+// becomes "1", ".", "2", "rc", "1", "-", "3".
+func tokenize(v string) []token {
+	var toks []token
+	start, cur := 0, -1
+	for i, r := range v {
+		if c := class(r); c != cur {
+			if i > 0 {
+				toks = append(toks, token{v[start:i], cur == 2})
+			}
+			start, cur = i, c
+		}
+	}
+	if start < len(v) {
+		toks = append(toks, token{v[start:], cur == 2})
+	}
+	return toks
+}
+
+// segments returns the alphanumeric tokens of v.
+func segments(v string) []string {
+	var segs []string
+	for _, t := range tokenize(v) {
+		if !t.sep {
+			segs = append(segs, t.text)
+		}
+	}
+	return segs
+}
+
+// Highlight returns newer with the first segment that differs from older
+// wrapped between open and close, such as "<b>" and "</b>" for Pango
+// markup: Highlight("1.2.3", "1.3.0", "<b>", "</b>") is "1.<b>3</b>.0".
+// The epoch, pkgver and pkgrel are compared separately, in that order.
+// newer is returned unchanged when no segment differs.
+func Highlight(older, newer, open, close string) string {
+	epoch, rest := "", newer
+	if e, v, ok := strings.Cut(newer, ":"); ok {
+		epoch, rest = e, v
+	}
+	ver, rel := rest, ""
+	if j := strings.LastIndexByte(rest, '-'); j >= 0 {
+		ver, rel = rest[:j], rest[j+1:]
+	}
+	oe, ov, or := parseEVR(older)
+	ne, _, _ := parseEVR(newer)
+	var b strings.Builder
+	if epoch != "" {
+		if oe != ne {
+			return open + epoch + close + ":" + rest
+		}
+		b.WriteString(epoch + ":")
+	}
+	h, changed := highlight(ov, ver, open, close)
+	b.WriteString(h)
+	if rel != "" {
+		if !changed {
+			rel, _ = highlight(or, rel, open, close)
+		}
+		b.WriteString("-" + rel)
+	}
+	return b.String()
+}
+
+// highlight wraps the first segment of newer that differs from older, and
+// reports whether there was one.
+func highlight(older, newer, open, close string) (string, bool) {
+	old := segments(older)
+	var b strings.Builder
+	seg, done := 0, false
+	for _, t := range tokenize(newer) {
+		if t.sep || done {
+			b.WriteString(t.text)
+			continue
+		}
+		if seg >= len(old) || old[seg] != t.text {
+			b.WriteString(open + t.text + close)
+			done = true
+		} else {
+			b.WriteString(t.text)
+		}
+		seg++
+	}
+	return b.String(), done
+}
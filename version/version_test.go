@@ -0,0 +1,22 @@
+package version
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		older, newer, want string
+	}{
+		{"1.2.3", "1.3.0", "1.<b>3</b>.0"},
+		{"1.2.3-1", "1.2.3-1", "1.2.3-1"},
+		{"1:1.0-1", "2:1.0-1", "<b>2</b>:1.0-1"},
+		{"1.0-1", "1:1.0-1", "<b>1</b>:1.0-1"},
+		{"1.2-1", "1.2.1-1", "1.2.<b>1</b>-1"},
+		{"1.2.3-1", "1.2.3-2", "1.2.3-<b>2</b>"},
+		{"1.0rc1-1", "1.0rc2-1", "1.0rc<b>2</b>-1"},
+	}
+	for _, tt := range tests {
+		if got := Highlight(tt.older, tt.newer, "<b>", "</b>"); got != tt.want {
+			t.Errorf("Highlight(%q, %q) = %q, want %q", tt.older, tt.newer, got, tt.want)
+		}
+	}
+}